@@ -5,6 +5,8 @@ go 1.26.0
 require (
 	github.com/launchdarkly/api-client-go/v13 v13.0.0
 	github.com/turbot/steampipe-plugin-sdk/v5 v5.14.0
+	golang.org/x/time v0.5.0
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
@@ -97,7 +99,6 @@ require (
 	golang.org/x/sync v0.18.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
 	golang.org/x/text v0.31.0 // indirect
-	golang.org/x/time v0.5.0 // indirect
 	golang.org/x/tools v0.38.0 // indirect
 	google.golang.org/api v0.171.0 // indirect
 	google.golang.org/genproto v0.0.0-20240227224415-6ceb2ff114de // indirect
@@ -105,5 +106,4 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240604185151-ef581f913117 // indirect
 	google.golang.org/grpc v1.66.0 // indirect
 	google.golang.org/protobuf v1.34.2 // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
@@ -5,7 +5,16 @@ import (
 )
 
 type launchdarklyConfig struct {
-	AccessToken *string `hcl:"access_token"`
+	AccessToken        *string  `hcl:"access_token"`
+	AccessTokenFile    *string  `hcl:"access_token_file"`
+	AccessTokenCommand *string  `hcl:"access_token_command"`
+	BaseURL            *string  `hcl:"base_url"`
+	MaxRetries         *int     `hcl:"max_retries"`
+	MinRetryDelay      *int     `hcl:"min_retry_delay"`
+	MaxRetryDelay      *int     `hcl:"max_retry_delay"`
+	Projects           []string `hcl:"projects,optional"`
+	Environments       []string `hcl:"environments,optional"`
+	IgnoreErrorCodes   []string `hcl:"ignore_error_codes,optional"`
 }
 
 func ConfigInstance() interface{} {
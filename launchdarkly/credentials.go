@@ -0,0 +1,192 @@
+package launchdarkly
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"gopkg.in/yaml.v2"
+)
+
+// Time allowed for access_token_command to print the access token
+const accessTokenCommandTimeout = 30 * time.Second
+
+// credentials are the access token of a connection and where it came from.
+type credentials struct {
+	AccessToken string
+	// Description of the source of the access token, used in error messages
+	Source string
+	// Base URL set alongside the access token, used if base_url is not set
+	BaseURL string
+}
+
+// connectionCredentials returns the access token of the connection, from the
+// first of these sources that is set:
+//
+//  1. access_token in the connection config
+//  2. access_token_file in the connection config
+//  3. access_token_command in the connection config
+//  4. the LAUNCHDARKLY_ACCESS_TOKEN environment variable
+//  5. the ldcli configuration file
+//
+// A source that is set but fails, e.g. a missing file, is an error rather
+// than falling through to the next source.
+func connectionCredentials(ctx context.Context, d *plugin.QueryData) (*credentials, error) {
+	// Commands are only run once per connection
+	cacheKey := "launchdarkly-credentials-" + d.Connection.Name
+	if cachedData, ok := d.ConnectionManager.Cache.Get(cacheKey); ok {
+		return cachedData.(*credentials), nil
+	}
+
+	creds, err := resolveCredentials(ctx, GetConfig(d.Connection))
+	if err != nil {
+		return nil, err
+	}
+
+	d.ConnectionManager.Cache.Set(cacheKey, creds)
+	return creds, nil
+}
+
+func resolveCredentials(ctx context.Context, config launchdarklyConfig) (*credentials, error) {
+	if config.AccessToken != nil {
+		if *config.AccessToken == "" {
+			return nil, errors.New("access_token is set but empty")
+		}
+		return &credentials{AccessToken: *config.AccessToken, Source: "access_token"}, nil
+	}
+
+	if config.AccessTokenFile != nil {
+		return accessTokenFromFile(*config.AccessTokenFile)
+	}
+
+	if config.AccessTokenCommand != nil {
+		return accessTokenFromCommand(ctx, *config.AccessTokenCommand)
+	}
+
+	if accessToken := os.Getenv("LAUNCHDARKLY_ACCESS_TOKEN"); accessToken != "" {
+		return &credentials{AccessToken: accessToken, Source: "LAUNCHDARKLY_ACCESS_TOKEN environment variable"}, nil
+	}
+
+	creds, err := accessTokenFromLdcliConfig()
+	if err != nil {
+		return nil, err
+	}
+	if creds == nil {
+		// Credentials not set
+		return nil, errors.New("access_token must be configured: set access_token, access_token_file or access_token_command in the connection config, set the LAUNCHDARKLY_ACCESS_TOKEN environment variable, or log in with ldcli")
+	}
+	return creds, nil
+}
+
+// accessTokenFromFile reads the access token from a file. Leading and
+// trailing whitespace, such as a trailing newline, is ignored.
+func accessTokenFromFile(path string) (*credentials, error) {
+	source := fmt.Sprintf("access_token_file %q", path)
+
+	path, err := expandHome(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", source, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", source, err)
+	}
+
+	accessToken := strings.TrimSpace(string(data))
+	if accessToken == "" {
+		return nil, fmt.Errorf("%s: file is empty", source)
+	}
+	return &credentials{AccessToken: accessToken, Source: source}, nil
+}
+
+// accessTokenFromCommand runs a shell command, such as a secrets manager CLI,
+// and uses its output as the access token.
+func accessTokenFromCommand(ctx context.Context, command string) (*credentials, error) {
+	source := fmt.Sprintf("access_token_command %q", command)
+
+	ctx, cancel := context.WithTimeout(ctx, accessTokenCommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%s: timed out after %s", source, accessTokenCommandTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %v: %s", source, err, msg)
+		}
+		return nil, fmt.Errorf("%s: %v", source, err)
+	}
+
+	accessToken := strings.TrimSpace(stdout.String())
+	if accessToken == "" {
+		return nil, fmt.Errorf("%s: command printed no access token", source)
+	}
+	return &credentials{AccessToken: accessToken, Source: source}, nil
+}
+
+// accessTokenFromLdcliConfig reads the access token and base URI saved by
+// "ldcli login" or "ldcli config --set". It returns nil if there is no ldcli
+// configuration file or it has no access token.
+func accessTokenFromLdcliConfig() (*credentials, error) {
+	path, err := ldcliConfigPath()
+	if err != nil {
+		return nil, nil
+	}
+	source := fmt.Sprintf("ldcli config file %q", path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: %v", source, err)
+	}
+
+	var config struct {
+		AccessToken string `yaml:"access-token"`
+		BaseURI     string `yaml:"base-uri"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%s: %v", source, err)
+	}
+	if config.AccessToken == "" {
+		return nil, nil
+	}
+	return &credentials{AccessToken: config.AccessToken, Source: source, BaseURL: config.BaseURI}, nil
+}
+
+// ldcliConfigPath returns the path of the ldcli configuration file,
+// $XDG_CONFIG_HOME/ldcli/config.yml or ~/.config/ldcli/config.yml.
+func ldcliConfigPath() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "ldcli", "config.yml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "ldcli", "config.yml"), nil
+}
+
+// expandHome replaces a leading ~ in path with the home directory.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
@@ -2,33 +2,160 @@ package launchdarkly
 
 import (
 	"context"
-	"strings"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
 
+	ldapi "github.com/launchdarkly/api-client-go/v13"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 )
 
-// shouldIgnoreErrors:: function which returns an ErrorPredicate for LaunchDarkly API calls
-func shouldIgnoreErrors(notFoundErrors []string) plugin.ErrorPredicateWithContext {
+// launchdarklyError is an error response from the LaunchDarkly API.
+type launchdarklyError struct {
+	// HTTP status code of the response
+	StatusCode int
+	// HTTP status line of the response, e.g. "404 Not Found"
+	Status string
+	// Error code and message from the JSON body of the response, if any
+	Code    string
+	Message string
+
+	err error
+}
+
+func (e *launchdarklyError) Error() string {
+	msg := e.Status
+	if hint := statusCodeHint(e.StatusCode); hint != "" {
+		msg += " (" + hint + ")"
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.Code != "" {
+		msg += fmt.Sprintf(" [code: %s]", e.Code)
+	}
+	return msg
+}
+
+func (e *launchdarklyError) Unwrap() error {
+	return e.err
+}
+
+// apiError converts an error returned by the LaunchDarkly API client into a
+// launchdarklyError, using the status code of resp and the error code and
+// message of the response body. Errors without an error response, such as
+// network errors, are returned unchanged.
+func apiError(resp *http.Response, err error) error {
+	if err == nil || resp == nil || resp.StatusCode < 300 {
+		return err
+	}
+
+	var body []byte
+	var openAPIErr *ldapi.GenericOpenAPIError
+	if errors.As(err, &openAPIErr) {
+		body = openAPIErr.Body()
+	}
+	return newLaunchdarklyError(resp, body, err)
+}
+
+// newLaunchdarklyError returns the launchdarklyError for an error response
+// and its body.
+func newLaunchdarklyError(resp *http.Response, body []byte, err error) *launchdarklyError {
+	apiErr := &launchdarklyError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		err:        err,
+	}
+
+	var rep struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &rep) == nil {
+		apiErr.Code = rep.Code
+		apiErr.Message = rep.Message
+	}
+	return apiErr
+}
+
+// statusCodeHint describes the usual cause of an error status code.
+func statusCodeHint(statusCode int) string {
+	switch {
+	case statusCode == http.StatusBadRequest:
+		return "invalid request"
+	case statusCode == http.StatusUnauthorized:
+		return "check that the access token is valid"
+	case statusCode == http.StatusForbidden:
+		return "the access token does not have permission for this resource"
+	case statusCode == http.StatusNotFound:
+		return "resource not found"
+	case statusCode == http.StatusConflict:
+		return "conflicting change"
+	case statusCode == http.StatusTooManyRequests:
+		return "rate limit exceeded"
+	case statusCode >= 500:
+		return "LaunchDarkly server error"
+	}
+	return ""
+}
+
+// errorStatusCode returns the HTTP status code of a LaunchDarkly API error,
+// or 0 if err is not an error response.
+func errorStatusCode(err error) int {
+	var apiErr *launchdarklyError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// shouldIgnoreErrors:: function which returns an ErrorPredicate for LaunchDarkly API calls.
+// Errors matching the ignore_error_codes connection option are also ignored.
+func shouldIgnoreErrors(statusCodes []int) plugin.ErrorPredicateWithContext {
 	return func(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData, err error) bool {
-		for _, pattern := range notFoundErrors {
-			// handle not found error
-			if strings.Contains(err.Error(), pattern) {
+		statusCode := errorStatusCode(err)
+		for _, code := range statusCodes {
+			if statusCode == code {
 				return true
 			}
 		}
+		return isIgnoredError(d, err)
+	}
+}
+
+// isIgnoredError reports whether err matches one of the ignore_error_codes of
+// the connection config, by HTTP status code, e.g. "403", or by LaunchDarkly
+// error code, e.g. "forbidden".
+func isIgnoredError(d *plugin.QueryData, err error) bool {
+	var apiErr *launchdarklyError
+	if !errors.As(err, &apiErr) {
 		return false
 	}
+
+	statusCode := strconv.Itoa(apiErr.StatusCode)
+	for _, code := range GetConfig(d.Connection).IgnoreErrorCodes {
+		if code == statusCode || (apiErr.Code != "" && code == apiErr.Code) {
+			return true
+		}
+	}
+	return false
 }
 
-func shouldRetryError(retryErrors []string) plugin.ErrorPredicateWithContext {
+func shouldRetryError(statusCodes []int) plugin.ErrorPredicateWithContext {
 	return func(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData, err error) bool {
-
-		for _, pattern := range retryErrors {
-			// handle not found error
-			if strings.Contains(err.Error(), pattern) {
+		statusCode := errorStatusCode(err)
+		for _, code := range statusCodes {
+			if statusCode == code {
 				return true
 			}
 		}
 		return false
 	}
-}
\ No newline at end of file
+}
+
+// isNotFoundError reports whether err is a LaunchDarkly "not found" response
+func isNotFoundError(err error) bool {
+	return errorStatusCode(err) == http.StatusNotFound
+}
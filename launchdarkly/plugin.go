@@ -2,6 +2,7 @@ package launchdarkly
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
@@ -13,20 +14,34 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 		Name:             "steampipe-plugin-launchdarkly",
 		DefaultTransform: transform.FromCamel(),
 		DefaultIgnoreConfig: &plugin.IgnoreConfig{
-			ShouldIgnoreErrorFunc: shouldIgnoreErrors([]string{"404"}),
+			ShouldIgnoreErrorFunc: shouldIgnoreErrors([]int{http.StatusNotFound}),
 		},
-		DefaultRetryConfig: &plugin.RetryConfig{ShouldRetryErrorFunc: shouldRetryError([]string{"429"})},
+		DefaultRetryConfig: &plugin.RetryConfig{ShouldRetryErrorFunc: shouldRetryError([]int{http.StatusTooManyRequests})},
 		ConnectionConfigSchema: &plugin.ConnectionConfigSchema{
 			NewInstance: ConfigInstance,
 		},
 		TableMap: map[string]*plugin.Table{
-			"launchdarkly_access_token":   tablelaunchdarklyAccessToken(ctx),
-			"launchdarkly_account_member": tablelaunchdarklyAccountMember(ctx),
-			"launchdarkly_audit_log":      tablelaunchdarklyAuditLog(ctx),
-			"launchdarkly_environment":    tablelaunchdarklyEnvironment(ctx),
-			"launchdarkly_feature_flag":   tablelaunchdarklyFeatureFlag(ctx),
-			"launchdarkly_project":        tablelaunchdarklyProject(ctx),
-			"launchdarkly_team":           tablelaunchdarklyTeam(ctx),
+			"launchdarkly_access_token":              tablelaunchdarklyAccessToken(ctx),
+			"launchdarkly_account_member":            tablelaunchdarklyAccountMember(ctx),
+			"launchdarkly_audit_log":                 tablelaunchdarklyAuditLog(ctx),
+			"launchdarkly_big_segment_import_export": tablelaunchdarklyBigSegmentImportExport(ctx),
+			"launchdarkly_caller_identity":           tablelaunchdarklyCallerIdentity(ctx),
+			"launchdarkly_connection_health":         tablelaunchdarklyConnectionHealth(ctx),
+			"launchdarkly_custom_role":               tablelaunchdarklyCustomRole(ctx),
+			"launchdarkly_environment":               tablelaunchdarklyEnvironment(ctx),
+			"launchdarkly_feature_flag":              tablelaunchdarklyFeatureFlag(ctx),
+			"launchdarkly_feature_flag_environment":  tablelaunchdarklyFeatureFlagEnvironment(ctx),
+			"launchdarkly_feature_flag_prerequisite": tablelaunchdarklyFeatureFlagPrerequisite(ctx),
+			"launchdarkly_feature_flag_rule":         tablelaunchdarklyFeatureFlagRule(ctx),
+			"launchdarkly_feature_flag_rule_clause":  tablelaunchdarklyFeatureFlagRuleClause(ctx),
+			"launchdarkly_feature_flag_status":       tablelaunchdarklyFeatureFlagStatus(ctx),
+			"launchdarkly_feature_flag_target":       tablelaunchdarklyFeatureFlagTarget(ctx),
+			"launchdarkly_permission_check":          tablelaunchdarklyPermissionCheck(ctx),
+			"launchdarkly_policy_statement":          tablelaunchdarklyPolicyStatement(ctx),
+			"launchdarkly_project":                   tablelaunchdarklyProject(ctx),
+			"launchdarkly_segment":                   tablelaunchdarklySegment(ctx),
+			"launchdarkly_segment_membership":        tablelaunchdarklySegmentMembership(ctx),
+			"launchdarkly_team":                      tablelaunchdarklyTeam(ctx),
 		},
 	}
 	return p
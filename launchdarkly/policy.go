@@ -0,0 +1,391 @@
+package launchdarkly
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+)
+
+// Types of the sources of policy statements, which are the principals and
+// resources that statements are defined in or assigned to.
+const (
+	policySourceAccessToken   = "access_token"
+	policySourceAccountMember = "account_member"
+	policySourceCustomRole    = "custom_role"
+	policySourceTeam          = "team"
+	policySourceWebhook       = "webhook"
+)
+
+// policySourceTypes lists the types of policy statement sources.
+var policySourceTypes = []string{
+	policySourceAccessToken,
+	policySourceAccountMember,
+	policySourceCustomRole,
+	policySourceTeam,
+	policySourceWebhook,
+}
+
+// policyStatement is a policy statement together with the source it applies
+// to.
+type policyStatement struct {
+	ldapi.Statement
+	SourceType string `json:"sourceType"`
+	SourceId   string `json:"sourceId"`
+	SourceName string `json:"sourceName,omitempty"`
+	// Key of the custom role the statement is defined in, if the statement
+	// comes from a custom role assigned to the source
+	RoleKey string `json:"roleKey,omitempty"`
+	// Index of the statement in the policy, inline role, permission grants or
+	// webhook statements it is defined in
+	StatementIndex int `json:"statementIndex"`
+	// Action set of a permission grant, if any
+	ActionSet string `json:"actionSet,omitempty"`
+}
+
+// customRoleStatements returns the statements of the policy of a custom role
+// assigned to a source.
+func customRoleStatements(sourceType string, sourceId string, sourceName string, role ldapi.CustomRole) []policyStatement {
+	statements := []policyStatement{}
+	for i, statement := range role.Policy {
+		statements = append(statements, policyStatement{
+			Statement:      statement,
+			SourceType:     sourceType,
+			SourceId:       sourceId,
+			SourceName:     sourceName,
+			RoleKey:        role.Key,
+			StatementIndex: i,
+		})
+	}
+	return statements
+}
+
+// accountMemberStatements returns the statements of the custom roles assigned
+// directly to a member and of the member's permission grants. Permission
+// grants only allow actions. The statements of the roles of the member's teams
+// are not included.
+func accountMemberStatements(member ldapi.Member, rolesByKey map[string]ldapi.CustomRole) []policyStatement {
+	name := member.Email
+	statements := []policyStatement{}
+	for _, key := range member.CustomRoles {
+		if role, ok := rolesByKey[key]; ok {
+			statements = append(statements, customRoleStatements(policySourceAccountMember, member.Id, name, role)...)
+		}
+	}
+	for i, grant := range member.PermissionGrants {
+		statements = append(statements, policyStatement{
+			Statement: ldapi.Statement{
+				Effect:    "allow",
+				Actions:   grant.Actions,
+				Resources: []string{grant.Resource},
+			},
+			SourceType:     policySourceAccountMember,
+			SourceId:       member.Id,
+			SourceName:     name,
+			StatementIndex: i,
+			ActionSet:      grant.ActionSet,
+		})
+	}
+	return statements
+}
+
+// accountMemberTeamStatements returns the statements of the custom roles of
+// the teams of a member.
+func accountMemberTeamStatements(member ldapi.Member, rolesByKey map[string]ldapi.CustomRole) []policyStatement {
+	statements := []policyStatement{}
+	for _, team := range member.Teams {
+		for _, key := range team.CustomRoleKeys {
+			if role, ok := rolesByKey[key]; ok {
+				statements = append(statements, customRoleStatements(policySourceTeam, team.Key, team.Name, role)...)
+			}
+		}
+	}
+	return statements
+}
+
+// accessTokenStatements returns the statements of the custom roles of an
+// access token and of its inline role.
+func accessTokenStatements(token ldapi.Token, rolesById map[string]ldapi.CustomRole) []policyStatement {
+	name := ""
+	if token.Name != nil {
+		name = *token.Name
+	}
+	statements := []policyStatement{}
+	for _, id := range token.CustomRoleIds {
+		if role, ok := rolesById[id]; ok {
+			statements = append(statements, customRoleStatements(policySourceAccessToken, token.Id, name, role)...)
+		}
+	}
+	for i, statement := range token.InlineRole {
+		statements = append(statements, policyStatement{
+			Statement:      statement,
+			SourceType:     policySourceAccessToken,
+			SourceId:       token.Id,
+			SourceName:     name,
+			StatementIndex: i,
+		})
+	}
+	return statements
+}
+
+// webhookStatements returns the statements that select the resources whose
+// changes a webhook is sent for.
+func webhookStatements(webhook ldapi.Webhook) []policyStatement {
+	name := ""
+	if webhook.Name != nil {
+		name = *webhook.Name
+	}
+	statements := []policyStatement{}
+	for i, statement := range webhook.Statements {
+		statements = append(statements, policyStatement{
+			Statement:      statement,
+			SourceType:     policySourceWebhook,
+			SourceId:       webhook.Id,
+			SourceName:     name,
+			StatementIndex: i,
+		})
+	}
+	return statements
+}
+
+// customRoleIndex holds every custom role of the account, in the order they
+// are listed and indexed by key and by ID.
+type customRoleIndex struct {
+	Roles []ldapi.CustomRole
+	ByKey map[string]ldapi.CustomRole
+	ById  map[string]ldapi.CustomRole
+}
+
+// fetchCustomRoleIndex fetches every custom role of the account.
+func fetchCustomRoleIndex(ctx context.Context, client *ldapi.APIClient) (*customRoleIndex, error) {
+	index := &customRoleIndex{
+		ByKey: map[string]ldapi.CustomRole{},
+		ById:  map[string]ldapi.CustomRole{},
+	}
+	err := getJSONPages(ctx, client, "/api/v2/roles", maxCustomRolesPageSize, func(role ldapi.CustomRole) bool {
+		index.Roles = append(index.Roles, role)
+		index.ByKey[role.Key] = role
+		index.ById[role.Id] = role
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// teamRoleKeys fetches the keys of the custom roles assigned to a team.
+func teamRoleKeys(ctx context.Context, client *ldapi.APIClient, teamKey string) ([]string, error) {
+	keys := []string{}
+	count := 0
+	params := client.TeamsApi.GetTeamRoles(ctx, teamKey)
+	for {
+		roles, resp, err := params.Execute()
+		if err != nil {
+			return nil, apiError(resp, err)
+		}
+		for _, role := range roles.Items {
+			if role.Key != nil {
+				keys = append(keys, *role.Key)
+			}
+		}
+		count += len(roles.Items)
+		if len(roles.Items) == 0 || roles.TotalCount == nil || count >= int(*roles.TotalCount) {
+			return keys, nil
+		}
+		params = params.Offset(int64(count))
+	}
+}
+
+// Reasons for a policy decision.
+const (
+	policyDecisionStatement    = "statement"
+	policyDecisionBuiltInRole  = "built_in_role"
+	policyDecisionImplicitDeny = "implicit_deny"
+)
+
+// writerRestrictedResourceTypes lists the types of resources the writer role
+// cannot change, which are managed by admins.
+var writerRestrictedResourceTypes = []string{"acct", "member", "role", "service-token", "team"}
+
+// policyDecision is the outcome of evaluating the statements and built-in
+// roles of a principal for an action on a resource.
+type policyDecision struct {
+	Allowed bool
+	// Why the action is allowed or denied: statement, built_in_role or
+	// implicit_deny
+	Reason string
+	// The statement that decided, for decisions by statement
+	Statement *policyStatement
+	// The built-in role that allowed the action, for decisions by built-in role
+	BuiltInRole string
+	// Every statement that matches the action and resource
+	MatchingStatements []policyStatement
+}
+
+// evaluatePolicy decides whether statements and builtInRoles allow action on
+// resource. As in LaunchDarkly, a matching deny statement takes precedence
+// over any allow, a matching allow statement takes precedence over the
+// built-in roles, and anything not allowed is denied.
+func evaluatePolicy(statements []policyStatement, builtInRoles []string, action string, resource []resourceSpecifierSegment) policyDecision {
+	decision := policyDecision{Reason: policyDecisionImplicitDeny, MatchingStatements: []policyStatement{}}
+
+	var allow, deny *policyStatement
+	for i, statement := range statements {
+		if !statementMatchesAction(statement.Statement, action) || !statementMatchesResource(statement.Statement, resource) {
+			continue
+		}
+		decision.MatchingStatements = append(decision.MatchingStatements, statement)
+		if strings.EqualFold(statement.Effect, "deny") && deny == nil {
+			deny = &statements[i]
+		} else if strings.EqualFold(statement.Effect, "allow") && allow == nil {
+			allow = &statements[i]
+		}
+	}
+
+	switch {
+	case deny != nil:
+		decision.Reason = policyDecisionStatement
+		decision.Statement = deny
+	case allow != nil:
+		decision.Allowed = true
+		decision.Reason = policyDecisionStatement
+		decision.Statement = allow
+	default:
+		for _, role := range builtInRoles {
+			if builtInRoleAllows(role, action, resource) {
+				decision.Allowed = true
+				decision.Reason = policyDecisionBuiltInRole
+				decision.BuiltInRole = role
+				break
+			}
+		}
+	}
+	return decision
+}
+
+// builtInRoleAllows reports whether a built-in role allows action on
+// resource. Owners and admins can do everything, writers can do everything
+// but manage the account, its members, teams, roles and service tokens, and
+// readers can only view. The reader role is approximated as allowing the
+// actions whose names start with view, such as viewProject.
+func builtInRoleAllows(role string, action string, resource []resourceSpecifierSegment) bool {
+	switch role {
+	case "owner", "admin":
+		return true
+	case "writer":
+		for _, segment := range resource {
+			for _, restricted := range writerRestrictedResourceTypes {
+				if segment.Type == restricted {
+					return false
+				}
+			}
+		}
+		return true
+	case "reader":
+		return strings.HasPrefix(action, "view")
+	}
+	return false
+}
+
+// statementMatchesAction reports whether a statement applies to action.
+func statementMatchesAction(statement ldapi.Statement, action string) bool {
+	if len(statement.Actions) > 0 {
+		return matchesAnyGlob(statement.Actions, action)
+	}
+	if len(statement.NotActions) > 0 {
+		return !matchesAnyGlob(statement.NotActions, action)
+	}
+	return false
+}
+
+// statementMatchesResource reports whether a statement applies to resource.
+func statementMatchesResource(statement ldapi.Statement, resource []resourceSpecifierSegment) bool {
+	if len(statement.Resources) > 0 {
+		for _, spec := range statement.Resources {
+			if resourceSpecifierMatches(parseResourceSpecifier(spec), resource) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(statement.NotResources) > 0 {
+		for _, spec := range statement.NotResources {
+			if resourceSpecifierMatches(parseResourceSpecifier(spec), resource) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// resourceSpecifierMatches reports whether the resource specifier pattern of
+// a statement matches resource. Each level must have the same type, a key
+// matching the key pattern, which may contain * wildcards, and at least one of
+// the tags of the pattern, if it has any.
+func resourceSpecifierMatches(pattern []resourceSpecifierSegment, resource []resourceSpecifierSegment) bool {
+	if len(pattern) != len(resource) {
+		return false
+	}
+	for i := range pattern {
+		if pattern[i].Type != resource[i].Type {
+			return false
+		}
+		if !matchesWildcard(pattern[i].Key, resource[i].Key) {
+			return false
+		}
+		if len(pattern[i].Tags) > 0 && !hasAnyTag(pattern[i].Tags, resource[i].Tags) {
+			return false
+		}
+	}
+	return true
+}
+
+// resourceSpecifierUsesTags reports whether any resource specifier of
+// statements matches resources by tag.
+func resourceSpecifierUsesTags(statements []policyStatement) bool {
+	for _, statement := range statements {
+		for _, spec := range append(append([]string{}, statement.Resources...), statement.NotResources...) {
+			for _, segment := range parseResourceSpecifier(spec) {
+				if len(segment.Tags) > 0 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func matchesAnyGlob(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matchesWildcard(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWildcard reports whether value matches pattern, in which * matches
+// any sequence of characters, including /, and every other character matches
+// itself.
+func matchesWildcard(pattern string, value string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == value
+	}
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	ok, _ := regexp.MatchString(expr, value)
+	return ok
+}
+
+func hasAnyTag(wanted []string, tags []string) bool {
+	for _, tag := range tags {
+		for _, w := range wanted {
+			if tag == w {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,390 @@
+package launchdarkly
+
+import (
+	"reflect"
+	"testing"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+)
+
+func TestParseResourceSpecifier(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want []resourceSpecifierSegment
+	}{
+		{
+			name: "empty",
+			spec: "",
+			want: nil,
+		},
+		{
+			name: "nested keys",
+			spec: "proj/web:env/production:flag/checkout",
+			want: []resourceSpecifierSegment{
+				{Type: "proj", Key: "web"},
+				{Type: "env", Key: "production"},
+				{Type: "flag", Key: "checkout"},
+			},
+		},
+		{
+			name: "tags",
+			spec: "proj/*;mobile,web:env/*",
+			want: []resourceSpecifierSegment{
+				{Type: "proj", Key: "*", Tags: []string{"mobile", "web"}},
+				{Type: "env", Key: "*"},
+			},
+		},
+		{
+			name: "no key",
+			spec: "acct",
+			want: []resourceSpecifierSegment{
+				{Type: "acct"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseResourceSpecifier(tt.spec); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseResourceSpecifier(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchesWildcard(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"checkout", "checkout", true},
+		{"checkout", "checkout-v2", false},
+		{"*", "", true},
+		{"*", "anything", true},
+		{"update*", "updateOn", true},
+		{"update*", "createFlag", false},
+		{"*-prod", "eu-prod", true},
+		{"a*c", "a/b/c", true},
+		{"a.c", "abc", false},
+		{"a?c", "abc", false},
+		{"a?c", "a?c", true},
+		{"[ab]", "a", false},
+		{"[ab]", "[ab]", true},
+	}
+
+	for _, tt := range tests {
+		if got := matchesWildcard(tt.pattern, tt.value); got != tt.want {
+			t.Errorf("matchesWildcard(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestResourceSpecifierMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		resource []resourceSpecifierSegment
+		want     bool
+	}{
+		{
+			name:    "exact",
+			pattern: "proj/web:env/production:flag/checkout",
+			resource: []resourceSpecifierSegment{
+				{Type: "proj", Key: "web"},
+				{Type: "env", Key: "production"},
+				{Type: "flag", Key: "checkout"},
+			},
+			want: true,
+		},
+		{
+			name:    "wildcard keys",
+			pattern: "proj/*:env/prod*:flag/*",
+			resource: []resourceSpecifierSegment{
+				{Type: "proj", Key: "web"},
+				{Type: "env", Key: "production"},
+				{Type: "flag", Key: "checkout"},
+			},
+			want: true,
+		},
+		{
+			name:    "different type",
+			pattern: "proj/*:env/*:segment/*",
+			resource: []resourceSpecifierSegment{
+				{Type: "proj", Key: "web"},
+				{Type: "env", Key: "production"},
+				{Type: "flag", Key: "checkout"},
+			},
+			want: false,
+		},
+		{
+			name:    "fewer segments",
+			pattern: "proj/*:env/*",
+			resource: []resourceSpecifierSegment{
+				{Type: "proj", Key: "web"},
+				{Type: "env", Key: "production"},
+				{Type: "flag", Key: "checkout"},
+			},
+			want: false,
+		},
+		{
+			name:    "more segments",
+			pattern: "proj/*:env/*:flag/*",
+			resource: []resourceSpecifierSegment{
+				{Type: "proj", Key: "web"},
+			},
+			want: false,
+		},
+		{
+			name:    "tag present",
+			pattern: "proj/*;mobile:env/*",
+			resource: []resourceSpecifierSegment{
+				{Type: "proj", Key: "web", Tags: []string{"web", "mobile"}},
+				{Type: "env", Key: "production"},
+			},
+			want: true,
+		},
+		{
+			name:    "tag missing",
+			pattern: "proj/*;mobile:env/*",
+			resource: []resourceSpecifierSegment{
+				{Type: "proj", Key: "web", Tags: []string{"web"}},
+				{Type: "env", Key: "production"},
+			},
+			want: false,
+		},
+		{
+			name:    "untagged resource",
+			pattern: "proj/*;mobile:env/*",
+			resource: []resourceSpecifierSegment{
+				{Type: "proj", Key: "web"},
+				{Type: "env", Key: "production"},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resourceSpecifierMatches(parseResourceSpecifier(tt.pattern), tt.resource); got != tt.want {
+				t.Errorf("resourceSpecifierMatches(%q) = %v, want %v", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatementMatches(t *testing.T) {
+	resource := parseResourceSpecifier("proj/web:env/production:flag/checkout")
+
+	tests := []struct {
+		name      string
+		statement ldapi.Statement
+		action    string
+		want      bool
+	}{
+		{
+			name:      "actions and resources",
+			statement: ldapi.Statement{Actions: []string{"updateOn"}, Resources: []string{"proj/*:env/*:flag/*"}},
+			action:    "updateOn",
+			want:      true,
+		},
+		{
+			name:      "other action",
+			statement: ldapi.Statement{Actions: []string{"updateOn"}, Resources: []string{"proj/*:env/*:flag/*"}},
+			action:    "deleteFlag",
+			want:      false,
+		},
+		{
+			name:      "not actions excludes action",
+			statement: ldapi.Statement{NotActions: []string{"updateOn"}, Resources: []string{"proj/*:env/*:flag/*"}},
+			action:    "updateOn",
+			want:      false,
+		},
+		{
+			name:      "not actions includes other action",
+			statement: ldapi.Statement{NotActions: []string{"updateOn"}, Resources: []string{"proj/*:env/*:flag/*"}},
+			action:    "deleteFlag",
+			want:      true,
+		},
+		{
+			name:      "not resources excludes resource",
+			statement: ldapi.Statement{Actions: []string{"*"}, NotResources: []string{"proj/*:env/production:flag/*"}},
+			action:    "updateOn",
+			want:      false,
+		},
+		{
+			name:      "not resources includes other resource",
+			statement: ldapi.Statement{Actions: []string{"*"}, NotResources: []string{"proj/*:env/test:flag/*"}},
+			action:    "updateOn",
+			want:      true,
+		},
+		{
+			name:      "no actions",
+			statement: ldapi.Statement{Resources: []string{"proj/*:env/*:flag/*"}},
+			action:    "updateOn",
+			want:      false,
+		},
+		{
+			name:      "no resources",
+			statement: ldapi.Statement{Actions: []string{"*"}},
+			action:    "updateOn",
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := statementMatchesAction(tt.statement, tt.action) && statementMatchesResource(tt.statement, resource)
+			if got != tt.want {
+				t.Errorf("statement matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuiltInRoleAllows(t *testing.T) {
+	flag := parseResourceSpecifier("proj/web:env/production:flag/checkout")
+	member := parseResourceSpecifier("member/abc")
+
+	tests := []struct {
+		role     string
+		action   string
+		resource []resourceSpecifierSegment
+		want     bool
+	}{
+		{"owner", "deleteMember", member, true},
+		{"admin", "deleteMember", member, true},
+		{"admin", "updateOn", flag, true},
+		{"writer", "updateOn", flag, true},
+		{"writer", "deleteMember", member, false},
+		{"reader", "viewProject", parseResourceSpecifier("proj/web"), true},
+		{"reader", "updateOn", flag, false},
+		{"no_access", "viewProject", parseResourceSpecifier("proj/web"), false},
+	}
+
+	for _, tt := range tests {
+		if got := builtInRoleAllows(tt.role, tt.action, tt.resource); got != tt.want {
+			t.Errorf("builtInRoleAllows(%q, %q) = %v, want %v", tt.role, tt.action, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluatePolicy(t *testing.T) {
+	resource := parseResourceSpecifier("proj/web:env/production:flag/checkout")
+	allow := policyStatement{
+		Statement:  ldapi.Statement{Effect: "allow", Actions: []string{"*"}, Resources: []string{"proj/*:env/*:flag/*"}},
+		SourceType: policySourceCustomRole,
+		SourceId:   "allow",
+	}
+	deny := policyStatement{
+		Statement:  ldapi.Statement{Effect: "deny", Actions: []string{"updateOn"}, Resources: []string{"proj/*:env/production:flag/*"}},
+		SourceType: policySourceCustomRole,
+		SourceId:   "deny",
+	}
+	other := policyStatement{
+		Statement:  ldapi.Statement{Effect: "deny", Actions: []string{"*"}, Resources: []string{"proj/*:env/test:flag/*"}},
+		SourceType: policySourceCustomRole,
+		SourceId:   "other",
+	}
+
+	tests := []struct {
+		name          string
+		statements    []policyStatement
+		builtInRoles  []string
+		action        string
+		wantAllowed   bool
+		wantReason    string
+		wantStatement string
+		wantRole      string
+		wantMatching  int
+	}{
+		{
+			name:          "deny beats allow",
+			statements:    []policyStatement{allow, deny},
+			action:        "updateOn",
+			wantReason:    policyDecisionStatement,
+			wantStatement: "deny",
+			wantMatching:  2,
+		},
+		{
+			name:          "deny beats built-in role",
+			statements:    []policyStatement{deny},
+			builtInRoles:  []string{"admin"},
+			action:        "updateOn",
+			wantReason:    policyDecisionStatement,
+			wantStatement: "deny",
+			wantMatching:  1,
+		},
+		{
+			name:          "allow",
+			statements:    []policyStatement{allow, deny},
+			action:        "updateFallthrough",
+			wantAllowed:   true,
+			wantReason:    policyDecisionStatement,
+			wantStatement: "allow",
+			wantMatching:  1,
+		},
+		{
+			name:          "allow beats built-in role",
+			statements:    []policyStatement{allow},
+			builtInRoles:  []string{"writer"},
+			action:        "updateOn",
+			wantAllowed:   true,
+			wantReason:    policyDecisionStatement,
+			wantStatement: "allow",
+			wantMatching:  1,
+		},
+		{
+			name:         "built-in role",
+			statements:   []policyStatement{other},
+			builtInRoles: []string{"reader", "writer"},
+			action:       "updateOn",
+			wantAllowed:  true,
+			wantReason:   policyDecisionBuiltInRole,
+			wantRole:     "writer",
+		},
+		{
+			name:         "reader cannot update",
+			builtInRoles: []string{"reader"},
+			action:       "updateOn",
+			wantReason:   policyDecisionImplicitDeny,
+		},
+		{
+			name:       "implicit deny",
+			statements: []policyStatement{other},
+			action:     "updateOn",
+			wantReason: policyDecisionImplicitDeny,
+		},
+		{
+			name:       "no statements",
+			action:     "updateOn",
+			wantReason: policyDecisionImplicitDeny,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluatePolicy(tt.statements, tt.builtInRoles, tt.action, resource)
+			if got.Allowed != tt.wantAllowed {
+				t.Errorf("Allowed = %v, want %v", got.Allowed, tt.wantAllowed)
+			}
+			if got.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", got.Reason, tt.wantReason)
+			}
+			statement := ""
+			if got.Statement != nil {
+				statement = got.Statement.SourceId
+			}
+			if statement != tt.wantStatement {
+				t.Errorf("Statement = %q, want %q", statement, tt.wantStatement)
+			}
+			if got.BuiltInRole != tt.wantRole {
+				t.Errorf("BuiltInRole = %q, want %q", got.BuiltInRole, tt.wantRole)
+			}
+			if len(got.MatchingStatements) != tt.wantMatching {
+				t.Errorf("len(MatchingStatements) = %d, want %d", len(got.MatchingStatements), tt.wantMatching)
+			}
+		})
+	}
+}
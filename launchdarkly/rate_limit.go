@@ -0,0 +1,169 @@
+package launchdarkly
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// Requests per second allowed by the token bucket shared by all tables of
+	// a connection, and the burst size of the bucket
+	rateLimitRequestsPerSecond = 10
+	rateLimitBurst             = 10
+
+	// Upper bound on a single wait for a rate limit to reset, in case of a
+	// bogus reset header
+	maxRateLimitWait = time.Minute
+
+	// Number of times a request rejected with 429 is sent again by the transport
+	maxRateLimitRetries = 3
+)
+
+// rateLimitTransport is an http.RoundTripper that throttles requests to the
+// LaunchDarkly API. Every request takes a token from a shared bucket, and the
+// X-Ratelimit-* and Retry-After response headers are used to pause requests
+// until an exhausted global or route limit resets.
+//
+// LaunchDarkly does not name the route a limit applies to, so routes are
+// approximated by request method and path.
+type rateLimitTransport struct {
+	base    http.RoundTripper
+	limiter *rate.Limiter
+
+	mu sync.Mutex
+	// Time each exhausted limit resets, keyed by route, or "" for the global limit
+	resets map[string]time.Time
+}
+
+func newRateLimitTransport(base http.RoundTripper) *rateLimitTransport {
+	return &rateLimitTransport{
+		base:    base,
+		limiter: rate.NewLimiter(rateLimitRequestsPerSecond, rateLimitBurst),
+		resets:  map[string]time.Time{},
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	route := req.Method + " " + req.URL.Path
+
+	for attempt := 0; ; attempt++ {
+		if err := t.wait(req.Context(), route); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		t.update(route, resp)
+
+		if resp.StatusCode != http.StatusTooManyRequests || attempt >= maxRateLimitRetries {
+			return resp, nil
+		}
+
+		// Requests with a body can only be sent again if the body can be recreated
+		retry := req.Clone(req.Context())
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			retry.Body = body
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		req = retry
+	}
+}
+
+// wait blocks until a token is available and any exhausted limit for the
+// route has reset.
+func (t *rateLimitTransport) wait(ctx context.Context, route string) error {
+	if err := t.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	t.mu.Lock()
+	reset := t.resets[""]
+	if r := t.resets[route]; r.After(reset) {
+		reset = r
+	}
+	for key, r := range t.resets {
+		if !r.After(now) {
+			delete(t.resets, key)
+		}
+	}
+	t.mu.Unlock()
+
+	delay := reset.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+	if delay > maxRateLimitWait {
+		delay = maxRateLimitWait
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// update records the limits exhausted by a response.
+func (t *rateLimitTransport) update(route string, resp *http.Response) {
+	globalExhausted := resp.Header.Get("X-Ratelimit-Global-Remaining") == "0"
+	routeExhausted := resp.Header.Get("X-Ratelimit-Route-Remaining") == "0" || resp.StatusCode == http.StatusTooManyRequests
+	if !globalExhausted && !routeExhausted {
+		return
+	}
+
+	reset := rateLimitReset(resp)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if globalExhausted {
+		t.resets[""] = reset
+	}
+	if routeExhausted {
+		t.resets[route] = reset
+	}
+}
+
+// rateLimitReset returns the time an exhausted limit resets, from the
+// Retry-After header in seconds or the X-Ratelimit-Reset header in Unix
+// milliseconds, defaulting to one second from now.
+func rateLimitReset(resp *http.Response) time.Time {
+	now := time.Now()
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return now.Add(time.Duration(seconds) * time.Second)
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return date
+		}
+	}
+
+	if reset := resp.Header.Get("X-Ratelimit-Reset"); reset != "" {
+		if ms, err := strconv.ParseInt(reset, 10, 64); err == nil {
+			return time.UnixMilli(ms)
+		}
+	}
+
+	return now.Add(time.Second)
+}
@@ -0,0 +1,59 @@
+package launchdarkly
+
+import (
+	"strings"
+)
+
+// resourceSpecifierSegment is one level of a LaunchDarkly resource specifier,
+// e.g. env/production;prod-tag in proj/web:env/production;prod-tag:flag/*
+type resourceSpecifierSegment struct {
+	// Resource type, e.g. proj, env or flag
+	Type string `json:"type"`
+	// Resource key or key pattern, e.g. production or *. Empty for resources
+	// without a key, such as acct.
+	Key string `json:"key"`
+	// Tags a resource must have to match the segment, if any
+	Tags []string `json:"tags,omitempty"`
+}
+
+// parseResourceSpecifier splits a resource specifier into its segments, from
+// the outermost resource to the innermost.
+func parseResourceSpecifier(spec string) []resourceSpecifierSegment {
+	if spec == "" {
+		return nil
+	}
+
+	segments := []resourceSpecifierSegment{}
+	for _, part := range strings.Split(spec, ":") {
+		segment := resourceSpecifierSegment{}
+		if name, tags, ok := strings.Cut(part, ";"); ok {
+			part = name
+			segment.Tags = strings.Split(tags, ",")
+		}
+		segment.Type, segment.Key, _ = strings.Cut(part, "/")
+		segments = append(segments, segment)
+	}
+	return segments
+}
+
+// resourceSpecifierKey returns the key of the segment of the given type, or
+// "" if the specifier has no segment of that type.
+func resourceSpecifierKey(segments []resourceSpecifierSegment, resourceType string) string {
+	for _, segment := range segments {
+		if segment.Type == resourceType {
+			return segment.Key
+		}
+	}
+	return ""
+}
+
+// resourceSpecifierTags returns the tags of the segment of the given type, or
+// nil if the specifier has no segment of that type.
+func resourceSpecifierTags(segments []resourceSpecifierSegment, resourceType string) []string {
+	for _, segment := range segments {
+		if segment.Type == resourceType {
+			return segment.Tags
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package launchdarkly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	// Defaults for the max_retries, min_retry_delay and max_retry_delay
+	// connection options. Delays are in milliseconds.
+	defaultMaxRetries    = 3
+	defaultMinRetryDelay = 500
+	defaultMaxRetryDelay = 10000
+)
+
+// retryPolicy controls how requests that fail with a transient error are
+// retried.
+type retryPolicy struct {
+	// Number of times a failed request is sent again
+	maxRetries int
+	// Delay before the first retry, doubled for every later retry up to maxDelay
+	minDelay time.Duration
+	maxDelay time.Duration
+}
+
+// connectionRetryPolicy returns the retry policy set by the max_retries,
+// min_retry_delay and max_retry_delay options of the connection config.
+func connectionRetryPolicy(config launchdarklyConfig) (retryPolicy, error) {
+	maxRetries := defaultMaxRetries
+	if config.MaxRetries != nil {
+		maxRetries = *config.MaxRetries
+	}
+	if maxRetries < 0 {
+		return retryPolicy{}, fmt.Errorf("max_retries must be 0 or greater, got %d", maxRetries)
+	}
+
+	minDelay := defaultMinRetryDelay
+	if config.MinRetryDelay != nil {
+		minDelay = *config.MinRetryDelay
+	}
+	if minDelay < 1 {
+		return retryPolicy{}, fmt.Errorf("min_retry_delay must be 1 or greater, got %d", minDelay)
+	}
+
+	maxDelay := defaultMaxRetryDelay
+	if config.MaxRetryDelay != nil {
+		maxDelay = *config.MaxRetryDelay
+	}
+	// Only the minimum delay being raised above the default maximum should
+	// raise the maximum, an explicit maximum below the minimum is an error
+	if config.MaxRetryDelay == nil && maxDelay < minDelay {
+		maxDelay = minDelay
+	}
+	if maxDelay < minDelay {
+		return retryPolicy{}, fmt.Errorf("max_retry_delay must be greater than or equal to min_retry_delay (%d), got %d", minDelay, maxDelay)
+	}
+
+	return retryPolicy{
+		maxRetries: maxRetries,
+		minDelay:   time.Duration(minDelay) * time.Millisecond,
+		maxDelay:   time.Duration(maxDelay) * time.Millisecond,
+	}, nil
+}
+
+// backoff returns the delay before the given retry, starting at 0. The
+// delay grows exponentially from minDelay and is capped at maxDelay, and a
+// random half of it is jittered so that concurrent requests that failed
+// together do not retry together.
+func (p retryPolicy) backoff(retry int) time.Duration {
+	delay := p.maxDelay
+	if retry < 32 {
+		if d := p.minDelay << uint(retry); d > 0 && d < p.maxDelay {
+			delay = d
+		}
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// retryTransport is an http.RoundTripper that retries requests failing with
+// a transient error, a 5xx response or a network error, with jittered
+// exponential backoff.
+//
+// Retrying each request, rather than the hydrate function that made it, means
+// a transient failure partway through a paginated list does not fail the
+// query, as the plugin SDK cannot retry a list once it has streamed rows.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy retryPolicy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for retry := 0; ; retry++ {
+		resp, err := t.base.RoundTrip(req)
+		if retry >= t.policy.maxRetries || !isIdempotent(req) || !isTransientError(req, resp, err) {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(t.policy.backoff(retry))
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// isIdempotent reports whether req can be sent again without side effects.
+// The plugin only reads from the API, but other requests are never retried.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// isTransientError reports whether a request failed in a way that may
+// succeed if it is sent again: a server error other than 501 Not Implemented,
+// or a network error such as a reset connection or a timeout. Errors caused
+// by the query being cancelled are not transient.
+func isTransientError(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil && !errors.Is(err, context.Canceled)
+	}
+	return resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+}
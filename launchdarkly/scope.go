@@ -0,0 +1,93 @@
+package launchdarkly
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+// A connection can be restricted to the projects and environments whose keys
+// match the glob patterns of the projects and environments connection
+// options, e.g. ["prod-*", "billing"]. Patterns use the syntax of path.Match.
+// A connection without patterns can access every project or environment.
+
+// validateScopePatterns checks the projects and environments patterns of the
+// connection config.
+func validateScopePatterns(config launchdarklyConfig) error {
+	for option, patterns := range map[string][]string{"projects": config.Projects, "environments": config.Environments} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%s contains an invalid pattern %q: %v", option, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// projectInScope reports whether the connection can access the project.
+func projectInScope(d *plugin.QueryData, projectKey string) bool {
+	return matchesAnyPattern(GetConfig(d.Connection).Projects, projectKey)
+}
+
+// environmentInScope reports whether the connection can access the environment.
+func environmentInScope(d *plugin.QueryData, environmentKey string) bool {
+	return matchesAnyPattern(GetConfig(d.Connection).Environments, environmentKey)
+}
+
+// scopedProjectKeys returns the keys of the projects the connection is
+// restricted to, if every projects pattern is a literal key. The projects can
+// then be fetched by key instead of enumerating every project in the account.
+func scopedProjectKeys(d *plugin.QueryData) []string {
+	patterns := GetConfig(d.Connection).Projects
+	if len(patterns) == 0 {
+		return nil
+	}
+	for _, pattern := range patterns {
+		if strings.ContainsAny(pattern, `*?[\`) {
+			return nil
+		}
+	}
+	return patterns
+}
+
+// scopeFeatureFlagEnvironments removes the environments the connection cannot
+// access from the per-environment configurations of a flag.
+func scopeFeatureFlagEnvironments(d *plugin.QueryData, flag *ldapi.FeatureFlag) {
+	for key := range flag.Environments {
+		if !environmentInScope(d, key) {
+			delete(flag.Environments, key)
+		}
+	}
+}
+
+// scopeProjectEnvironments removes the environments the connection cannot
+// access from the expanded environments of a project.
+func scopeProjectEnvironments(d *plugin.QueryData, project *ldapi.Project) {
+	if project.Environments == nil {
+		return
+	}
+	items := []ldapi.Environment{}
+	for _, environment := range project.Environments.Items {
+		if environmentInScope(d, environment.Key) {
+			items = append(items, environment)
+		}
+	}
+	count := int32(len(items))
+	project.Environments.Items = items
+	project.Environments.TotalCount = &count
+}
+
+func matchesAnyPattern(patterns []string, key string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -2,8 +2,14 @@ package launchdarkly
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
 
 	ldapi "github.com/launchdarkly/api-client-go/v13"
 
@@ -18,34 +24,228 @@ func connect(ctx context.Context, d *plugin.QueryData) (*ldapi.APIClient, error)
 	return conn.(*ldapi.APIClient), nil
 }
 
+// connectBeta returns a client which opts in to LaunchDarkly beta resources.
+// Beta resources reject requests without the LD-API-Version header, but the
+// header must not be sent to stable resources, so it uses a separate client.
+func connectBeta(ctx context.Context, d *plugin.QueryData) (*ldapi.APIClient, error) {
+	conn, err := betaConnectionCached(ctx, d, nil)
+	if err != nil {
+		return nil, err
+	}
+	return conn.(*ldapi.APIClient), nil
+}
+
 var connectionCached = plugin.HydrateFunc(connectionUncached).Memoize()
 
+var betaConnectionCached = plugin.HydrateFunc(betaConnectionUncached).Memoize()
+
 func connectionUncached(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (any, error) {
 
-	// Load connection from cache, which preserves throttling protection etc
-	cacheKey := "launchdarkly"
+	// Load connection from cache, which preserves throttling protection etc.
+	// The cache is shared by every connection of the plugin, so the key must
+	// include the connection name to keep their credentials apart.
+	cacheKey := "launchdarkly-" + d.Connection.Name
 	if cachedData, ok := d.ConnectionManager.Cache.Get(cacheKey); ok {
 		return cachedData.(*ldapi.APIClient), nil
 	}
 
-	// Default to using env vars (#2)
-	accessToken := os.Getenv("LAUNCHDARKLY_ACCESS_TOKEN")
+	cfg, err := clientConfiguration(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	conn := ldapi.NewAPIClient(cfg)
+
+	if err := validateAccessToken(ctx, d, conn); err != nil {
+		return nil, err
+	}
 
-	// But prefer the config (#1)
-	launchdarklyConfig := GetConfig(d.Connection)
-	if launchdarklyConfig.AccessToken != nil {
-		accessToken = *launchdarklyConfig.AccessToken
+	d.ConnectionManager.Cache.Set(cacheKey, conn)
+	return conn, nil
+}
+
+func betaConnectionUncached(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (any, error) {
+
+	// Load connection from cache, which preserves throttling protection etc
+	cacheKey := "launchdarkly-beta-" + d.Connection.Name
+	if cachedData, ok := d.ConnectionManager.Cache.Get(cacheKey); ok {
+		return cachedData.(*ldapi.APIClient), nil
 	}
 
-	if accessToken == "" {
-		// Credentials not set
-		return nil, errors.New("access_token must be configured")
+	// Validate the access token with the stable client first
+	if _, err := connect(ctx, d); err != nil {
+		return nil, err
 	}
 
-	cfg := ldapi.NewConfiguration()
-	cfg.AddDefaultHeader("Authorization", accessToken)
+	cfg, err := clientConfiguration(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	cfg.AddDefaultHeader("LD-API-Version", "beta")
 	conn := ldapi.NewAPIClient(cfg)
 
 	d.ConnectionManager.Cache.Set(cacheKey, conn)
 	return conn, nil
 }
+
+// validateAccessToken checks the access token of the connection when the
+// client is created, so a bad token fails with a message naming where the
+// token came from instead of a 401 from whichever table runs first.
+func validateAccessToken(ctx context.Context, d *plugin.QueryData, client *ldapi.APIClient) error {
+	_, err := getCallerIdentity(ctx, client)
+	if err == nil {
+		return nil
+	}
+
+	if errorStatusCode(err) == http.StatusUnauthorized {
+		creds, credsErr := connectionCredentials(ctx, d)
+		if credsErr != nil {
+			return credsErr
+		}
+		return fmt.Errorf("the access token from %s was rejected: %w", creds.Source, err)
+	}
+
+	// Other errors, such as an instance without the caller identity endpoint,
+	// are left for the table queries to surface
+	plugin.Logger(ctx).Warn("launchdarkly.validateAccessToken", "api_error", err)
+	return nil
+}
+
+// clientConfiguration builds the API client configuration for the connection.
+func clientConfiguration(ctx context.Context, d *plugin.QueryData) (*ldapi.Configuration, error) {
+	creds, err := connectionCredentials(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// Default to the commercial instance, https://app.launchdarkly.com, or the
+	// instance the ldcli configuration file points to
+	launchdarklyConfig := GetConfig(d.Connection)
+	baseURL := creds.BaseURL
+	if v := os.Getenv("LAUNCHDARKLY_BASE_URL"); v != "" {
+		baseURL = v
+	}
+	if launchdarklyConfig.BaseURL != nil {
+		baseURL = *launchdarklyConfig.BaseURL
+	}
+
+	if err := validateScopePatterns(launchdarklyConfig); err != nil {
+		return nil, err
+	}
+
+	transport, err := connectionTransport(d)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := ldapi.NewConfiguration()
+	cfg.AddDefaultHeader("Authorization", creds.AccessToken)
+	cfg.HTTPClient = &http.Client{Transport: transport}
+
+	if baseURL != "" {
+		u, err := url.Parse(baseURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("base_url must be an absolute URL such as https://app.launchdarkly.us, got %q", baseURL)
+		}
+		cfg.Servers = ldapi.ServerConfigurations{
+			{
+				URL: strings.TrimSuffix(baseURL, "/"),
+			},
+		}
+	}
+	return cfg, nil
+}
+
+// connectionTransport returns the HTTP transport of the connection, which
+// retries transient errors and throttles requests. The transport is shared by
+// the clients used by every table of a connection, so they share one rate
+// limit token bucket.
+func connectionTransport(d *plugin.QueryData) (http.RoundTripper, error) {
+	cacheKey := "launchdarkly-transport-" + d.Connection.Name
+	if cachedData, ok := d.ConnectionManager.Cache.Get(cacheKey); ok {
+		return cachedData.(http.RoundTripper), nil
+	}
+
+	policy, err := connectionRetryPolicy(GetConfig(d.Connection))
+	if err != nil {
+		return nil, err
+	}
+
+	// Every retry goes through the rate limiter, which also waits for rate
+	// limits to reset and resends requests rejected with 429
+	transport := &retryTransport{
+		base:   newRateLimitTransport(http.DefaultTransport),
+		policy: policy,
+	}
+	d.ConnectionManager.Cache.Set(cacheKey, transport)
+	return transport, nil
+}
+
+// getJSON fetches a path of the LaunchDarkly API, e.g. /api/v2/roles?offset=20,
+// with the headers and transport of client and decodes the response into v.
+// It serves resources and query parameters the API client does not support.
+func getJSON(ctx context.Context, client *ldapi.APIClient, path string, v interface{}) error {
+	cfg := client.GetConfig()
+	basePath, err := cfg.ServerURLWithContext(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, basePath+path, nil)
+	if err != nil {
+		return err
+	}
+	for header, value := range cfg.DefaultHeader {
+		req.Header.Set(header, value)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := cfg.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return newLaunchdarklyError(resp, body, errors.New(resp.Status))
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+// getJSONPages calls handler with each item listed at a path of the
+// LaunchDarkly API, fetching pages of limit items with the limit and offset
+// query parameters until a page is empty or short, or handler returns false.
+// The API client lists only the first page of some resources, such as custom
+// roles, segments and access tokens, which are paged through this instead.
+func getJSONPages[T any](ctx context.Context, client *ldapi.APIClient, path string, limit int64, handler func(T) bool) error {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+
+	offset := 0
+	for {
+		var page struct {
+			Items []T `json:"items"`
+		}
+		err := getJSON(ctx, client, fmt.Sprintf("%s%slimit=%d&offset=%d", path, separator, limit, offset), &page)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range page.Items {
+			if !handler(item) {
+				return nil
+			}
+		}
+
+		if len(page.Items) == 0 || len(page.Items) < int(limit) {
+			return nil
+		}
+		offset += len(page.Items)
+	}
+}
@@ -3,6 +3,7 @@ package launchdarkly
 import (
 	"context"
 
+	ldapi "github.com/launchdarkly/api-client-go/v13"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
@@ -116,6 +117,9 @@ func tablelaunchdarklyAccessToken(_ context.Context) *plugin.Table {
 	}
 }
 
+// maxAccessTokensPageSize is the page size used to list access tokens.
+const maxAccessTokensPageSize = 20
+
 //// LIST FUNCTION
 
 func listAccessTokens(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
@@ -128,14 +132,20 @@ func listAccessTokens(ctx context.Context, d *plugin.QueryData, h *plugin.Hydrat
 		return nil, err
 	}
 
-	tokens, _, err := client.AccessTokensApi.GetTokens(ctx).Execute()
+	limit := int64(maxAccessTokensPageSize)
+	if d.QueryContext.Limit != nil && *d.QueryContext.Limit < limit {
+		limit = *d.QueryContext.Limit
+	}
+
+	err = getJSONPages(ctx, client, "/api/v2/tokens", limit, func(token ldapi.Token) bool {
+		d.StreamListItem(ctx, token)
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		return d.RowsRemaining(ctx) != 0
+	})
 	if err != nil {
 		logger.Error("launchdarkly_access_token.listAccessTokens", "api_error", err)
 		return nil, err
 	}
-	for _, item := range tokens.Items {
-		d.StreamListItem(ctx, item)
-	}
 
 	return nil, nil
 }
@@ -150,8 +160,9 @@ func getAccessToken(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateD
 		return nil, err
 	}
 
-	token, _, err := client.AccessTokensApi.GetToken(ctx, id).Execute()
+	token, resp, err := client.AccessTokensApi.GetToken(ctx, id).Execute()
 	if err != nil {
+		err = apiError(resp, err)
 		logger.Error("launchdarkly_access_token.listAccessTokens", "api_error", err)
 		return nil, err
 	}
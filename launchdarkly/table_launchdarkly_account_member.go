@@ -137,8 +137,9 @@ func listAccountMembers(ctx context.Context, d *plugin.QueryData, h *plugin.Hydr
 	count := 0
 
 	for {
-		accountMembers, _, err := params.Execute()
+		accountMembers, resp, err := params.Execute()
 		if err != nil {
+			err = apiError(resp, err)
 			logger.Error("launchdarkly_account_member.listAccountMembers", "api_error", err)
 			return nil, err
 		}
@@ -171,8 +172,9 @@ func getAccountMember(ctx context.Context, d *plugin.QueryData, h *plugin.Hydrat
 		return nil, err
 	}
 
-	member, _, err := client.AccountMembersApi.GetMember(ctx, memberId).Execute()
+	member, resp, err := client.AccountMembersApi.GetMember(ctx, memberId).Execute()
 	if err != nil {
+		err = apiError(resp, err)
 		logger.Error("launchdarkly_account_member.getAccountMember", "api_error", err)
 		return nil, err
 	}
@@ -2,12 +2,25 @@ package launchdarkly
 
 import (
 	"context"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
 
+	ldapi "github.com/launchdarkly/api-client-go/v13"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
 )
 
+const (
+	// Largest page of audit log entries the API returns
+	maxAuditLogPageSize = 20
+
+	// Longest time range fetched by a single series of audit log requests
+	auditLogWindowSize = 30 * 24 * time.Hour
+)
+
 //// TABLE DEFINITION
 
 func tablelaunchdarklyAuditLog(_ context.Context) *plugin.Table {
@@ -30,6 +43,30 @@ func tablelaunchdarklyAuditLog(_ context.Context) *plugin.Table {
 					Name:    "query",
 					Require: plugin.Optional,
 				},
+				{
+					Name:    "resource_type",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "project_key",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "environment_key",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "resource_key",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "action",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "member_email",
+					Require: plugin.Optional,
+				},
 			},
 		},
 		Get: &plugin.GetConfig{
@@ -101,6 +138,42 @@ func tablelaunchdarklyAuditLog(_ context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromQual("query"),
 			},
+			{
+				Name:        "resource_type",
+				Description: "The type of the resource acted on, such as proj, env, flag or segment.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromP(auditLogResourceValue, "resource_type"),
+			},
+			{
+				Name:        "project_key",
+				Description: "The key of the project of the resource acted on, if any.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromP(auditLogResourceValue, "project_key"),
+			},
+			{
+				Name:        "environment_key",
+				Description: "The key of the environment of the resource acted on, if any.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromP(auditLogResourceValue, "environment_key"),
+			},
+			{
+				Name:        "resource_key",
+				Description: "The key of the resource acted on.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromP(auditLogResourceValue, "resource_key"),
+			},
+			{
+				Name:        "action",
+				Description: "The action performed on the resource, such as updateOn or createFlag. If the entry records several actions, this is the first, or the first that matches the quals.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromP(auditLogResourceValue, "action"),
+			},
+			{
+				Name:        "member_email",
+				Description: "The email address of the member who made the change, if any.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Member.Email"),
+			},
 			{
 				Name:        "accesses",
 				Description: "Details on the actions performed and resources acted on in this audit log entry.",
@@ -142,6 +215,36 @@ func tablelaunchdarklyAuditLog(_ context.Context) *plugin.Table {
 				Type:        proto.ColumnType_JSON,
 				Transform:   transform.FromJSONTag(),
 			},
+			{
+				Name:        "delta",
+				Description: "The JSON patch body of the request that updated the resource, if the audit log entry records an update.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getAuditLogEntryDetails,
+			},
+			{
+				Name:        "merge",
+				Description: "The merge information of the audit log entry, if any.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getAuditLogEntryDetails,
+			},
+			{
+				Name:        "trigger_body",
+				Description: "The external trigger of the audit log entry, if any.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getAuditLogEntryDetails,
+			},
+			{
+				Name:        "previous_version",
+				Description: "The resource before the change, if the audit log entry records an update.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getAuditLogEntryDetails,
+			},
+			{
+				Name:        "current_version",
+				Description: "The resource after the change, if the audit log entry records an update.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getAuditLogEntryDetails,
+			},
 			// Steampipe standard columns
 			{
 				Name:        "title",
@@ -167,60 +270,340 @@ func listAuditLogs(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDa
 
 	params := client.AuditLogApi.GetAuditLogEntries(ctx)
 
+	// Add support for optional spec qual, or compose a resource specifier
+	// from the resource quals
+	spec := d.EqualsQualString("spec")
+	if spec == "" {
+		spec = auditLogSpec(
+			d.EqualsQualString("resource_type"),
+			d.EqualsQualString("project_key"),
+			d.EqualsQualString("environment_key"),
+			d.EqualsQualString("resource_key"),
+		)
+	}
+	if spec != "" {
+		params = params.Spec(spec)
+	}
+
+	// The search query matches member email addresses, so it can narrow down
+	// the entries of a member if no other query is given
+	query := d.EqualsQualString("query")
+	if query == "" {
+		query = d.EqualsQualString("member_email")
+	}
+	if query != "" {
+		params = params.Q(query)
+	}
+
+	pageSize := int64(maxAuditLogPageSize)
+	if d.QueryContext.Limit != nil && *d.QueryContext.Limit < pageSize {
+		pageSize = *d.QueryContext.Limit
+	}
+	params = params.Limit(pageSize)
+
 	// Add support for optional date quals
+	after, before := auditLogDateBounds(auditLogDateQuals(d.Quals["date"]))
+
+	for _, window := range auditLogWindows(after, before, time.Now()) {
+		more, err := listAuditLogWindow(ctx, d, params, window)
+		if err != nil {
+			logger.Error("launchdarkly_audit_log.listAuditLogs", "api_error", err)
+			return nil, err
+		}
+		if !more {
+			break
+		}
+	}
+
+	return nil, nil
+}
+
+// auditLogWindow is a time range of audit log entries, as the exclusive after
+// and before bounds in Unix milliseconds used by the API. A nil bound is open.
+type auditLogWindow struct {
+	After  *int64
+	Before *int64
+}
+
+// listAuditLogWindow streams the audit log entries of a time window, newest
+// first, following the next link of each page. It returns false if no more
+// rows are needed.
+func listAuditLogWindow(ctx context.Context, d *plugin.QueryData, params ldapi.ApiGetAuditLogEntriesRequest, window auditLogWindow) (bool, error) {
+	if window.After != nil {
+		params = params.After(*window.After)
+	}
+	if window.Before != nil {
+		params = params.Before(*window.Before)
+	}
+	before := window.Before
+
+	for {
+		entries, resp, err := params.Execute()
+		if err != nil {
+			return false, apiError(resp, err)
+		}
+
+		for _, item := range entries.Items {
+			if !auditLogEntryMatchesQuals(d, item) {
+				continue
+			}
+			d.StreamListItem(ctx, item)
 
-	if d.Quals["date"] != nil {
-		for _, q := range d.Quals["date"].Quals {
-			givenTime := q.Value.GetTimestampValue().GetSeconds()
-			givenTimeMs := givenTime * 1000
-			beforeTimeMs := (givenTime - 1) * 1000
-			afterTimeMs := (givenTime + 1) * 1000
-
-			switch q.Operator {
-			case ">":
-				params = params.After(afterTimeMs)
-			case ">=":
-				params = params.After(afterTimeMs)
-			case "=":
-				params = params.Before(givenTimeMs)
-				params = params.After(givenTimeMs)
-			case "<=":
-				params = params.Before(beforeTimeMs)
-			case "<":
-				params = params.Before(beforeTimeMs)
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return false, nil
 			}
 		}
+
+		// The next link pages back in time by moving the before bound
+		next, ok := entries.Links["next"]
+		if !ok || len(entries.Items) == 0 {
+			return true, nil
+		}
+		nextBefore, ok := auditLogNextBefore(next.GetHref())
+		if !ok || (before != nil && nextBefore >= *before) {
+			return true, nil
+		}
+		before = &nextBefore
+		params = params.Before(nextBefore)
+	}
+}
+
+// Resource types that belong to an environment or a project, and so are
+// named by resource specifiers of the form proj/*:env/*:flag/* or proj/*:metric/*
+var (
+	auditLogEnvironmentResourceTypes = []string{"flag", "segment", "experiment", "holdout"}
+	auditLogProjectResourceTypes     = []string{"metric", "metric-group", "context-kind", "release-pipeline", "layer"}
+)
+
+// auditLogSpec composes the resource specifier that matches resources of a
+// type, in a project and environment if given, e.g. proj/web:env/production:flag/*
+// for flags in the production environment of the web project. It returns ""
+// if resourceType is not set, as resource specifiers always name a type and
+// the API takes a single specifier, so project_key and environment_key alone
+// are only filtered by the plugin.
+func auditLogSpec(resourceType string, projectKey string, environmentKey string, resourceKey string) string {
+	orAny := func(key string) string {
+		if key == "" {
+			return "*"
+		}
+		return key
+	}
+
+	switch {
+	case resourceType == "":
+		return ""
+	case resourceType == "proj":
+		if resourceKey == "" {
+			resourceKey = projectKey
+		}
+		return "proj/" + orAny(resourceKey)
+	case resourceType == "env":
+		if resourceKey == "" {
+			resourceKey = environmentKey
+		}
+		return "proj/" + orAny(projectKey) + ":env/" + orAny(resourceKey)
+	case slices.Contains(auditLogEnvironmentResourceTypes, resourceType):
+		return "proj/" + orAny(projectKey) + ":env/" + orAny(environmentKey) + ":" + resourceType + "/" + orAny(resourceKey)
+	case slices.Contains(auditLogProjectResourceTypes, resourceType):
+		return "proj/" + orAny(projectKey) + ":" + resourceType + "/" + orAny(resourceKey)
 	}
+	return resourceType + "/" + orAny(resourceKey)
+}
+
+// auditLogResource describes the resource acted on by an audit log entry.
+type auditLogResource struct {
+	Type           string
+	Key            string
+	ProjectKey     string
+	EnvironmentKey string
+	Action         string
+}
+
+// auditLogResourceColumns lists the columns that describe the resource and
+// action of an access of an audit log entry.
+var auditLogResourceColumns = []string{"resource_type", "project_key", "environment_key", "resource_key", "action"}
 
-	// Add support for optional spec qual
-	if d.EqualsQuals["spec"] != nil {
-		params = params.Spec(d.EqualsQuals["spec"].GetStringValue())
+// getAuditLogResource returns the resource of an access of an audit log entry,
+// from its resource specifier, e.g. proj/web:env/production:flag/dark-mode
+func getAuditLogResource(access ldapi.ResourceAccess) auditLogResource {
+	resource := auditLogResource{Action: access.GetAction()}
+	segments := parseResourceSpecifier(access.GetResource())
+	if len(segments) > 0 {
+		resource.Type = segments[len(segments)-1].Type
+		resource.Key = segments[len(segments)-1].Key
 	}
+	resource.ProjectKey = resourceSpecifierKey(segments, "proj")
+	resource.EnvironmentKey = resourceSpecifierKey(segments, "env")
+	return resource
+}
 
-	if d.EqualsQuals["spec"] != nil {
-		params = params.Q(d.EqualsQuals["query"].GetStringValue())
+// columnValue returns the value of a resource column of the resource.
+func (r auditLogResource) columnValue(column string) string {
+	switch column {
+	case "resource_type":
+		return r.Type
+	case "project_key":
+		return r.ProjectKey
+	case "environment_key":
+		return r.EnvironmentKey
+	case "resource_key":
+		return r.Key
+	case "action":
+		return r.Action
 	}
+	return ""
+}
 
-	if d.QueryContext.Limit != nil {
-		limit := int32(*d.QueryContext.Limit)
+// matchAuditLogResource returns the resource of the first access of an audit
+// log entry that has the given values of the resource columns, and whether
+// there is one. An entry may record several accesses, e.g. of a flag and of
+// its environment, and matches if any of them does.
+func matchAuditLogResource(accesses []ldapi.ResourceAccess, values map[string]string) (auditLogResource, bool) {
+	if len(accesses) == 0 {
+		return auditLogResource{}, len(values) == 0
+	}
+	for _, access := range accesses {
+		resource := getAuditLogResource(access)
+		matches := true
+		for column, value := range values {
+			if resource.columnValue(column) != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return resource, true
+		}
+	}
+	return getAuditLogResource(accesses[0]), false
+}
 
-		maxAuditLogs := int64(20)
+// auditLogEntryMatchesQuals reports whether an audit log entry matches the
+// resource, action and member quals, which the API only filters by
+// approximately, if at all.
+func auditLogEntryMatchesQuals(d *plugin.QueryData, item ldapi.AuditLogEntryListingRep) bool {
+	if d.EqualsQuals["member_email"] != nil && d.EqualsQualString("member_email") != item.Member.GetEmail() {
+		return false
+	}
 
-		if limit < int32(maxAuditLogs) {
-			params = params.Limit(int64(limit))
+	values := map[string]string{}
+	for _, column := range auditLogResourceColumns {
+		if d.EqualsQuals[column] != nil {
+			values[column] = d.EqualsQualString(column)
 		}
 	}
+	_, ok := matchAuditLogResource(item.Accesses, values)
+	return ok
+}
 
-	auditLogs, _, err := params.Execute()
+// auditLogNextBefore returns the before bound of the next link of a page of
+// audit log entries, e.g. /api/v2/auditlog?before=1700000000000&limit=20
+func auditLogNextBefore(href string) (int64, bool) {
+	u, err := url.Parse(href)
 	if err != nil {
-		logger.Error("launchdarkly_audit_log.listAuditLogs", "api_error", err)
-		return nil, err
+		return 0, false
 	}
-	for _, item := range auditLogs.Items {
-		d.StreamListItem(ctx, item)
+	before, err := strconv.ParseInt(u.Query().Get("before"), 10, 64)
+	if err != nil {
+		return 0, false
 	}
+	return before, true
+}
 
-	return nil, nil
+// auditLogDateQual is a comparison of the date column, e.g. date >= t.
+type auditLogDateQual struct {
+	Operator string
+	Time     time.Time
+}
+
+// auditLogDateQuals returns the comparisons of the date quals.
+func auditLogDateQuals(quals *plugin.KeyColumnQuals) []auditLogDateQual {
+	if quals == nil {
+		return nil
+	}
+	dateQuals := []auditLogDateQual{}
+	for _, q := range quals.Quals {
+		dateQuals = append(dateQuals, auditLogDateQual{
+			Operator: q.Operator,
+			Time:     q.Value.GetTimestampValue().AsTime(),
+		})
+	}
+	return dateQuals
+}
+
+// auditLogDateBounds converts the date quals to the after and before bounds
+// of the API, which are exclusive and in Unix milliseconds. The bounds are
+// rounded outwards, never inwards, so that every matching entry is fetched;
+// Postgres filters out any extra entries.
+func auditLogDateBounds(quals []auditLogDateQual) (*int64, *int64) {
+	var after, before *int64
+
+	setAfter := func(ms int64) {
+		if after == nil || ms > *after {
+			after = &ms
+		}
+	}
+	setBefore := func(ms int64) {
+		if before == nil || ms < *before {
+			before = &ms
+		}
+	}
+
+	for _, q := range quals {
+		t := q.Time
+		floorMs := t.UnixMilli()
+		ceilMs := floorMs
+		if t.Sub(time.UnixMilli(floorMs)) > 0 {
+			ceilMs++
+		}
+
+		switch q.Operator {
+		case ">":
+			// date > t includes every millisecond after floor(t)
+			setAfter(floorMs)
+		case ">=":
+			setAfter(ceilMs - 1)
+		case "=":
+			setAfter(ceilMs - 1)
+			setBefore(floorMs + 1)
+		case "<=":
+			setBefore(floorMs + 1)
+		case "<":
+			setBefore(ceilMs)
+		}
+	}
+	return after, before
+}
+
+// auditLogWindows splits the time range between the after and before bounds
+// into windows of at most auditLogWindowSize, newest first, so that a long
+// time range is fetched with several smaller requests. A range without an
+// after bound is fetched in a single window.
+func auditLogWindows(after *int64, before *int64, now time.Time) []auditLogWindow {
+	if after == nil {
+		return []auditLogWindow{{Before: before}}
+	}
+
+	upper := now.UnixMilli() + 1
+	if before != nil {
+		upper = *before
+	}
+
+	windows := []auditLogWindow{}
+	windowSize := auditLogWindowSize.Milliseconds()
+	for upper > *after+1 {
+		windowBefore := upper
+		windowAfter := *after
+		if windowBefore-windowAfter > windowSize {
+			windowAfter = windowBefore - windowSize
+		}
+		windows = append(windows, auditLogWindow{After: &windowAfter, Before: &windowBefore})
+
+		// The next window ends just after this window starts
+		upper = windowAfter + 1
+	}
+	return windows
 }
 
 func getAuditLog(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
@@ -233,11 +616,72 @@ func getAuditLog(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData
 		return nil, err
 	}
 
-	auditLog, _, err := client.AuditLogApi.GetAuditLogEntry(ctx, id).Execute()
+	auditLog, resp, err := client.AuditLogApi.GetAuditLogEntry(ctx, id).Execute()
 	if err != nil {
+		err = apiError(resp, err)
 		logger.Error("launchdarkly_audit_log.getAuditLog", "api_error", err)
 		return nil, err
 	}
 
 	return auditLog, nil
 }
+
+// getAuditLogEntryDetails fetches the change details of an audit log entry,
+// which are only included in the response of a single entry.
+func getAuditLogEntryDetails(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	var id string
+	switch item := h.Item.(type) {
+	case *ldapi.AuditLogEntryRep:
+		// Entries fetched by getAuditLog already include the details
+		return item, nil
+	case ldapi.AuditLogEntryListingRep:
+		id = item.Id
+	}
+
+	// Create client
+	client, err := connect(ctx, d)
+	if err != nil {
+		logger.Error("launchdarkly_audit_log.getAuditLogEntryDetails", "connection_error", err)
+		return nil, err
+	}
+
+	auditLog, resp, err := client.AuditLogApi.GetAuditLogEntry(ctx, id).Execute()
+	if err != nil {
+		err = apiError(resp, err)
+		logger.Error("launchdarkly_audit_log.getAuditLogEntryDetails", "api_error", err)
+		return nil, err
+	}
+
+	return auditLog, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func auditLogResourceValue(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	var accesses []ldapi.ResourceAccess
+	switch item := d.HydrateItem.(type) {
+	case ldapi.AuditLogEntryListingRep:
+		accesses = item.Accesses
+	case *ldapi.AuditLogEntryRep:
+		accesses = item.Accesses
+	}
+
+	// Describe the access that matched the resource quals, as rows are listed
+	// if any access matches them
+	values := map[string]string{}
+	for _, column := range auditLogResourceColumns {
+		for _, q := range d.KeyColumnQuals[column] {
+			if q.Operator == "=" {
+				values[column] = q.Value.GetStringValue()
+			}
+		}
+	}
+	resource, _ := matchAuditLogResource(accesses, values)
+	value := resource.columnValue(d.Param.(string))
+	if value == "" {
+		return nil, nil
+	}
+	return value, nil
+}
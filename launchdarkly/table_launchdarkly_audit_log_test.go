@@ -0,0 +1,237 @@
+package launchdarkly
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuditLogDateBounds(t *testing.T) {
+	// A time on a millisecond, and a time between two milliseconds
+	onMs := time.UnixMilli(1700000000000)
+	betweenMs := onMs.Add(500 * time.Microsecond)
+
+	tests := []struct {
+		name       string
+		quals      []auditLogDateQual
+		wantAfter  *int64
+		wantBefore *int64
+	}{
+		{
+			name: "no quals",
+		},
+		{
+			name:      "greater than",
+			quals:     []auditLogDateQual{{">", onMs}},
+			wantAfter: ptr(int64(1700000000000)),
+		},
+		{
+			name:      "greater than between milliseconds",
+			quals:     []auditLogDateQual{{">", betweenMs}},
+			wantAfter: ptr(int64(1700000000000)),
+		},
+		{
+			name:      "greater than or equal",
+			quals:     []auditLogDateQual{{">=", onMs}},
+			wantAfter: ptr(int64(1699999999999)),
+		},
+		{
+			name:      "greater than or equal between milliseconds",
+			quals:     []auditLogDateQual{{">=", betweenMs}},
+			wantAfter: ptr(int64(1700000000000)),
+		},
+		{
+			name:       "less than",
+			quals:      []auditLogDateQual{{"<", onMs}},
+			wantBefore: ptr(int64(1700000000000)),
+		},
+		{
+			name:       "less than between milliseconds",
+			quals:      []auditLogDateQual{{"<", betweenMs}},
+			wantBefore: ptr(int64(1700000000001)),
+		},
+		{
+			name:       "less than or equal",
+			quals:      []auditLogDateQual{{"<=", onMs}},
+			wantBefore: ptr(int64(1700000000001)),
+		},
+		{
+			name:       "less than or equal between milliseconds",
+			quals:      []auditLogDateQual{{"<=", betweenMs}},
+			wantBefore: ptr(int64(1700000000001)),
+		},
+		{
+			name:       "equal",
+			quals:      []auditLogDateQual{{"=", onMs}},
+			wantAfter:  ptr(int64(1699999999999)),
+			wantBefore: ptr(int64(1700000000001)),
+		},
+		{
+			name: "narrowest of several bounds",
+			quals: []auditLogDateQual{
+				{">", onMs.Add(-time.Hour)},
+				{">=", onMs},
+				{"<", onMs.Add(2 * time.Hour)},
+				{"<=", onMs.Add(time.Hour)},
+			},
+			wantAfter:  ptr(int64(1699999999999)),
+			wantBefore: ptr(int64(1700003600001)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after, before := auditLogDateBounds(tt.quals)
+			if !equalBound(after, tt.wantAfter) {
+				t.Errorf("after = %v, want %v", formatBound(after), formatBound(tt.wantAfter))
+			}
+			if !equalBound(before, tt.wantBefore) {
+				t.Errorf("before = %v, want %v", formatBound(before), formatBound(tt.wantBefore))
+			}
+		})
+	}
+}
+
+// TestAuditLogDateBoundsInclusion checks that the exclusive bounds include
+// exactly the milliseconds that satisfy each qual.
+func TestAuditLogDateBoundsInclusion(t *testing.T) {
+	base := time.UnixMilli(1700000000000)
+	for _, offset := range []time.Duration{0, 500 * time.Microsecond} {
+		qualTime := base.Add(offset)
+		for _, operator := range []string{">", ">=", "=", "<", "<="} {
+			after, before := auditLogDateBounds([]auditLogDateQual{{operator, qualTime}})
+			for ms := base.UnixMilli() - 2; ms <= base.UnixMilli()+2; ms++ {
+				entry := time.UnixMilli(ms)
+				var want bool
+				switch operator {
+				case ">":
+					want = entry.After(qualTime)
+				case ">=":
+					want = !entry.Before(qualTime)
+				case "=":
+					want = entry.Equal(qualTime)
+				case "<":
+					want = entry.Before(qualTime)
+				case "<=":
+					want = !entry.After(qualTime)
+				}
+				got := (after == nil || ms > *after) && (before == nil || ms < *before)
+				if got != want {
+					t.Errorf("date %s %v: entry at %d fetched = %v, want %v", operator, qualTime, ms, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestAuditLogWindows(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	windowSize := auditLogWindowSize.Milliseconds()
+
+	tests := []struct {
+		name        string
+		after       *int64
+		before      *int64
+		wantWindows int
+	}{
+		{
+			name:        "no bounds",
+			wantWindows: 1,
+		},
+		{
+			name:        "before only",
+			before:      ptr(now.UnixMilli() - 1000),
+			wantWindows: 1,
+		},
+		{
+			name:        "shorter than a window",
+			after:       ptr(now.UnixMilli() - windowSize/2),
+			wantWindows: 1,
+		},
+		{
+			name:        "exactly one window",
+			after:       ptr(now.UnixMilli() + 1 - windowSize),
+			wantWindows: 1,
+		},
+		{
+			name:        "one millisecond more than a window",
+			after:       ptr(now.UnixMilli() - windowSize),
+			wantWindows: 2,
+		},
+		{
+			name:        "several windows",
+			after:       ptr(now.UnixMilli() - 95*24*time.Hour.Milliseconds()),
+			before:      ptr(now.UnixMilli() - 1000),
+			wantWindows: 4,
+		},
+		{
+			name:        "empty range",
+			after:       ptr(now.UnixMilli() - 1000),
+			before:      ptr(now.UnixMilli() - 999),
+			wantWindows: 0,
+		},
+		{
+			name:        "single millisecond",
+			after:       ptr(now.UnixMilli() - 1000),
+			before:      ptr(now.UnixMilli() - 998),
+			wantWindows: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windows := auditLogWindows(tt.after, tt.before, now)
+			if len(windows) != tt.wantWindows {
+				t.Fatalf("got %d windows, want %d", len(windows), tt.wantWindows)
+			}
+			if tt.after == nil {
+				if !equalBound(windows[0].After, nil) || !equalBound(windows[0].Before, tt.before) {
+					t.Errorf("window = (%v, %v), want (nil, %v)", formatBound(windows[0].After), formatBound(windows[0].Before), formatBound(tt.before))
+				}
+				return
+			}
+			if len(windows) == 0 {
+				return
+			}
+
+			// The windows are newest first, and together cover every millisecond
+			// between the bounds exactly once
+			upper := now.UnixMilli() + 1
+			if tt.before != nil {
+				upper = *tt.before
+			}
+			if *windows[0].Before != upper {
+				t.Errorf("first window before = %d, want %d", *windows[0].Before, upper)
+			}
+			if last := windows[len(windows)-1]; *last.After != *tt.after {
+				t.Errorf("last window after = %d, want %d", *last.After, *tt.after)
+			}
+			for i, window := range windows {
+				size := *window.Before - *window.After - 1
+				if size <= 0 || size > windowSize {
+					t.Errorf("window %d covers %d ms, want between 1 and %d", i, size, windowSize)
+				}
+				if i > 0 && *window.Before != *windows[i-1].After+1 {
+					t.Errorf("window %d before = %d, want %d to follow window %d", i, *window.Before, *windows[i-1].After+1, i-1)
+				}
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func equalBound(a *int64, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func formatBound(b *int64) interface{} {
+	if b == nil {
+		return nil
+	}
+	return *b
+}
@@ -0,0 +1,229 @@
+package launchdarkly
+
+import (
+	"context"
+	"net/http"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tablelaunchdarklyBigSegmentImportExport(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "launchdarkly_big_segment_import_export",
+		Description: "Fetch the status of big segment import and export jobs.",
+		List: &plugin.ListConfig{
+			Hydrate: listBigSegmentImportExports,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "project_key", Require: plugin.Required},
+				{Name: "environment_key", Require: plugin.Required},
+				{Name: "segment_key", Require: plugin.Required},
+				{Name: "id", Require: plugin.Required},
+				{Name: "type", Require: plugin.Optional},
+			},
+			RetryConfig: &plugin.RetryConfig{
+				ShouldRetryErrorFunc: shouldRetryError([]int{http.StatusTooManyRequests}),
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "id",
+				Description: "The ID of the import or export.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "type",
+				Description: "The type of the job: import or export.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "project_key",
+				Description: "The key of the project.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "environment_key",
+				Description: "The key of the environment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "segment_key",
+				Description: "The key of the big segment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "status",
+				Description: "The status of the import or export.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "creation_time",
+				Description: "Time when the import or export was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("CreationTime").Transform(transform.UnixMsToTimestamp),
+			},
+			{
+				Name:        "mode",
+				Description: "For imports, the import mode: merge or replace.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "size_bytes",
+				Description: "For exports, the size of the exported file, in bytes.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "size",
+				Description: "For exports, the size of the exported file, with units.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "initiator_name",
+				Description: "For exports, the name of the member who started the export.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Initiator.Name"),
+			},
+			{
+				Name:        "initiator_email",
+				Description: "For exports, the email address of the member who started the export.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Initiator.Email"),
+			},
+			{
+				Name:        "files",
+				Description: "For imports, the imported files and their status.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "links",
+				Description: "The location and content type of related resources, including the location of the exported file.",
+				Type:        proto.ColumnType_JSON,
+			},
+			// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "Title of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Id"),
+			},
+		},
+	}
+}
+
+const (
+	bigSegmentJobTypeImport = "import"
+	bigSegmentJobTypeExport = "export"
+)
+
+// launchdarklyBigSegmentImportExport holds the fields of both an import and
+// an export, so that both job types share a row shape.
+type launchdarklyBigSegmentImportExport struct {
+	Id             string
+	Type           string
+	ProjectKey     string
+	EnvironmentKey string
+	SegmentKey     string
+	Status         string
+	CreationTime   int64
+	Mode           string
+	SizeBytes      *int64
+	Size           string
+	Initiator      *ldapi.InitiatorRep
+	Files          []ldapi.FileRep
+	Links          map[string]ldapi.Link
+}
+
+//// LIST FUNCTION
+
+func listBigSegmentImportExports(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	projectKey := d.EqualsQualString("project_key")
+	environmentKey := d.EqualsQualString("environment_key")
+	segmentKey := d.EqualsQualString("segment_key")
+	if !projectInScope(d, projectKey) || !environmentInScope(d, environmentKey) {
+		return nil, nil
+	}
+
+	jobTypes := []string{bigSegmentJobTypeImport, bigSegmentJobTypeExport}
+	if d.EqualsQuals["type"] != nil {
+		jobTypes = []string{d.EqualsQualString("type")}
+	}
+
+	// Create client
+	client, err := connectBeta(ctx, d)
+	if err != nil {
+		logger.Error("launchdarkly_big_segment_import_export.listBigSegmentImportExports", "connection_error", err)
+		return nil, err
+	}
+
+	// The API has no way to list the jobs of a segment, so each ID is fetched
+	// as an import and as an export, skipping the type it is not
+	for _, id := range getQualStringList(d, "id") {
+		for _, jobType := range jobTypes {
+			var job *launchdarklyBigSegmentImportExport
+			var resp *http.Response
+			switch jobType {
+			case bigSegmentJobTypeImport:
+				var item *ldapi.Import
+				item, resp, err = client.SegmentsBetaApi.GetBigSegmentImport(ctx, projectKey, environmentKey, segmentKey, id).Execute()
+				if err == nil {
+					job = &launchdarklyBigSegmentImportExport{
+						Id:           item.Id,
+						Type:         jobType,
+						SegmentKey:   item.SegmentKey,
+						Status:       item.Status,
+						CreationTime: item.CreationTime,
+						Mode:         item.Mode,
+						Files:        item.Files,
+						Links:        item.Links,
+					}
+				}
+			case bigSegmentJobTypeExport:
+				var item *ldapi.Export
+				item, resp, err = client.SegmentsBetaApi.GetBigSegmentExport(ctx, projectKey, environmentKey, segmentKey, id).Execute()
+				if err == nil {
+					job = &launchdarklyBigSegmentImportExport{
+						Id:           item.Id,
+						Type:         jobType,
+						SegmentKey:   item.SegmentKey,
+						Status:       item.Status,
+						CreationTime: item.CreationTime,
+						SizeBytes:    &item.SizeBytes,
+						Size:         item.Size,
+						Initiator:    &item.Initiator,
+						Links:        item.Links,
+					}
+				}
+			default:
+				continue
+			}
+			if err != nil {
+				err = apiError(resp, err)
+				if isNotFoundError(err) {
+					continue
+				}
+				logger.Error("launchdarkly_big_segment_import_export.listBigSegmentImportExports", "api_error", err)
+				return nil, err
+			}
+
+			job.ProjectKey = projectKey
+			job.EnvironmentKey = environmentKey
+			if job.SegmentKey == "" {
+				job.SegmentKey = segmentKey
+			}
+			d.StreamListItem(ctx, *job)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
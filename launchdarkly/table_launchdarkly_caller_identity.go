@@ -0,0 +1,167 @@
+package launchdarkly
+
+import (
+	"context"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tablelaunchdarklyCallerIdentity(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "launchdarkly_caller_identity",
+		Description: "Fetch the identity and scope of the access token used by the connection.",
+		List: &plugin.ListConfig{
+			Hydrate: listCallerIdentity,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "account_id",
+				Description: "The ID of the account the access token belongs to.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "auth_kind",
+				Description: "The kind of credential used, such as token.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "token_id",
+				Description: "The ID of the access token.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "token_name",
+				Description: "The name of the access token.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "token_kind",
+				Description: "The kind of access token, such as personal or service.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "service_token",
+				Description: "Whether the access token is a service token, which is not tied to an account member.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "member_id",
+				Description: "The ID of the account member the access token belongs to.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "client_id",
+				Description: "The ID of the OAuth client, if the credential was issued to one.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "project_id",
+				Description: "The ID of the project the credential is restricted to, if any.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "project_name",
+				Description: "The name of the project the credential is restricted to, if any.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "environment_id",
+				Description: "The ID of the environment the credential is restricted to, if any.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "environment_name",
+				Description: "The name of the environment the credential is restricted to, if any.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "credential_source",
+				Description: "Where the plugin read the access token from, such as access_token or the LAUNCHDARKLY_ACCESS_TOKEN environment variable.",
+				Type:        proto.ColumnType_STRING,
+			},
+			// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "Title of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(callerIdentityTitle),
+			},
+		},
+	}
+}
+
+// callerIdentity is the response of the caller identity endpoint, which the
+// API client does not support.
+type callerIdentity struct {
+	AccountId       string `json:"accountId"`
+	AuthKind        string `json:"authKind"`
+	TokenId         string `json:"tokenId"`
+	TokenName       string `json:"tokenName"`
+	TokenKind       string `json:"tokenKind"`
+	ServiceToken    bool   `json:"serviceToken"`
+	MemberId        string `json:"memberId"`
+	ClientId        string `json:"clientId"`
+	ProjectId       string `json:"projectId"`
+	ProjectName     string `json:"projectName"`
+	EnvironmentId   string `json:"environmentId"`
+	EnvironmentName string `json:"environmentName"`
+}
+
+type launchdarklyCallerIdentity struct {
+	callerIdentity
+	CredentialSource string
+}
+
+//// LIST FUNCTION
+
+func listCallerIdentity(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	// Create client
+	client, err := connect(ctx, d)
+	if err != nil {
+		logger.Error("launchdarkly_caller_identity.listCallerIdentity", "connection_error", err)
+		return nil, err
+	}
+
+	identity, err := getCallerIdentity(ctx, client)
+	if err != nil {
+		logger.Error("launchdarkly_caller_identity.listCallerIdentity", "api_error", err)
+		return nil, err
+	}
+
+	creds, err := connectionCredentials(ctx, d)
+	if err != nil {
+		logger.Error("launchdarkly_caller_identity.listCallerIdentity", "connection_error", err)
+		return nil, err
+	}
+
+	d.StreamListItem(ctx, launchdarklyCallerIdentity{*identity, creds.Source})
+
+	return nil, nil
+}
+
+// getCallerIdentity fetches the identity of the access token of client from
+// GET /api/v2/caller-identity.
+func getCallerIdentity(ctx context.Context, client *ldapi.APIClient) (*callerIdentity, error) {
+	var identity callerIdentity
+	if err := getJSON(ctx, client, "/api/v2/caller-identity", &identity); err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func callerIdentityTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	identity := d.HydrateItem.(launchdarklyCallerIdentity)
+	if identity.TokenName != "" {
+		return identity.TokenName, nil
+	}
+	return identity.TokenId, nil
+}
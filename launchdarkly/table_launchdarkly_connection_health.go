@@ -0,0 +1,365 @@
+package launchdarkly
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tablelaunchdarklyConnectionHealth(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "launchdarkly_connection_health",
+		Description: "Check whether the access token of the connection can read each API used by the plugin's tables.",
+		List: &plugin.ListConfig{
+			Hydrate: listConnectionHealth,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "api",
+				Description: "The name of the API that was probed, such as feature_flags or account_members.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "status",
+				Description: "The result of the probe: reachable, forbidden if the access token does not have permission, error for any other failure, or skipped if there was no project, environment or flag to probe the API with.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "status_code",
+				Description: "The HTTP status code of the response, if any.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "latency_ms",
+				Description: "The time taken by the probe request in milliseconds, including any rate limit waits and retries.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "error",
+				Description: "The error returned by the probe, if any.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "project_key",
+				Description: "The key of the project the API was probed with, for project-scoped APIs.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "environment_key",
+				Description: "The key of the environment the API was probed with, for environment-scoped APIs.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "tables",
+				Description: "The tables, or table columns, that use the API.",
+				Type:        proto.ColumnType_JSON,
+			},
+			// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "Title of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Api"),
+			},
+		},
+	}
+}
+
+type launchdarklyConnectionHealth struct {
+	Api            string
+	Status         string
+	StatusCode     *int
+	LatencyMs      *int64
+	Error          *string
+	ProjectKey     *string
+	EnvironmentKey *string
+	Tables         []string
+}
+
+// connectionHealthTarget holds the resources found by earlier probes, which
+// later probes of project, environment and flag scoped APIs are made against.
+type connectionHealthTarget struct {
+	ProjectKey     string
+	EnvironmentKey string
+	FlagKey        string
+	// Patterns of the projects and environments the connection is restricted
+	// to, if any
+	ProjectPatterns     []string
+	EnvironmentPatterns []string
+}
+
+// connectionHealthProbe makes the cheapest read request of an API. Probes
+// return the response of the request, and update the target with any
+// resource later probes need.
+type connectionHealthProbe struct {
+	Api    string
+	Tables []string
+	// Whether the probe needs a project, environment or flag to be found first
+	NeedsProject     bool
+	NeedsEnvironment bool
+	NeedsFlag        bool
+	Probe            func(ctx context.Context, client *ldapi.APIClient, beta *ldapi.APIClient, target *connectionHealthTarget) (*http.Response, error)
+}
+
+// connectionHealthProbes lists a probe for each API used by the tables of the
+// plugin, in the order they are run.
+var connectionHealthProbes = []connectionHealthProbe{
+	{
+		Api:    "caller_identity",
+		Tables: []string{"launchdarkly_caller_identity"},
+		Probe: func(ctx context.Context, client *ldapi.APIClient, _ *ldapi.APIClient, _ *connectionHealthTarget) (*http.Response, error) {
+			_, err := getCallerIdentity(ctx, client)
+			return nil, err
+		},
+	},
+	{
+		Api:    "projects",
+		Tables: []string{"launchdarkly_project"},
+		Probe: func(ctx context.Context, client *ldapi.APIClient, _ *ldapi.APIClient, target *connectionHealthTarget) (*http.Response, error) {
+			// Page through the projects until one in the connection scope is
+			// found, leaving the project scoped APIs skipped if there is none
+			count := 0
+			params := client.ProjectsApi.GetProjects(ctx)
+			for {
+				projects, resp, err := params.Execute()
+				if err != nil || target.ProjectKey != "" {
+					return resp, err
+				}
+				for _, project := range projects.Items {
+					if matchesAnyPattern(target.ProjectPatterns, project.Key) {
+						target.ProjectKey = project.Key
+						return resp, nil
+					}
+				}
+				count += len(projects.Items)
+				if len(projects.Items) == 0 || count >= int(projects.GetTotalCount()) {
+					return resp, nil
+				}
+				params = params.Offset(int64(count))
+			}
+		},
+	},
+	{
+		Api:          "flag_defaults",
+		Tables:       []string{"launchdarkly_project.flag_defaults"},
+		NeedsProject: true,
+		Probe: func(ctx context.Context, client *ldapi.APIClient, _ *ldapi.APIClient, target *connectionHealthTarget) (*http.Response, error) {
+			_, resp, err := client.ProjectsApi.GetFlagDefaultsByProject(ctx, target.ProjectKey).Execute()
+			return resp, err
+		},
+	},
+	{
+		Api:          "environments",
+		Tables:       []string{"launchdarkly_environment"},
+		NeedsProject: true,
+		Probe: func(ctx context.Context, client *ldapi.APIClient, _ *ldapi.APIClient, target *connectionHealthTarget) (*http.Response, error) {
+			// Page through the environments until one in the connection scope is
+			// found, leaving the environment scoped APIs skipped if there is none
+			count := 0
+			params := client.EnvironmentsApi.GetEnvironmentsByProject(ctx, target.ProjectKey)
+			for {
+				environments, resp, err := params.Execute()
+				if err != nil || target.EnvironmentKey != "" {
+					return resp, err
+				}
+				for _, environment := range environments.Items {
+					if matchesAnyPattern(target.EnvironmentPatterns, environment.Key) {
+						target.EnvironmentKey = environment.Key
+						return resp, nil
+					}
+				}
+				count += len(environments.Items)
+				if len(environments.Items) == 0 || count >= int(environments.GetTotalCount()) {
+					return resp, nil
+				}
+				params = params.Offset(int64(count))
+			}
+		},
+	},
+	{
+		Api: "feature_flags",
+		Tables: []string{
+			"launchdarkly_feature_flag",
+			"launchdarkly_feature_flag_environment",
+			"launchdarkly_feature_flag_prerequisite",
+			"launchdarkly_feature_flag_rule",
+			"launchdarkly_feature_flag_rule_clause",
+			"launchdarkly_feature_flag_target",
+		},
+		NeedsProject: true,
+		Probe: func(ctx context.Context, client *ldapi.APIClient, _ *ldapi.APIClient, target *connectionHealthTarget) (*http.Response, error) {
+			flags, resp, err := client.FeatureFlagsApi.GetFeatureFlags(ctx, target.ProjectKey).Limit(1).Execute()
+			if err == nil && len(flags.Items) > 0 {
+				target.FlagKey = flags.Items[0].Key
+			}
+			return resp, err
+		},
+	},
+	{
+		Api:              "feature_flag_statuses",
+		Tables:           []string{"launchdarkly_feature_flag_status"},
+		NeedsProject:     true,
+		NeedsEnvironment: true,
+		Probe: func(ctx context.Context, client *ldapi.APIClient, _ *ldapi.APIClient, target *connectionHealthTarget) (*http.Response, error) {
+			_, resp, err := client.FeatureFlagsApi.GetFeatureFlagStatuses(ctx, target.ProjectKey, target.EnvironmentKey).Execute()
+			return resp, err
+		},
+	},
+	{
+		Api:              "dependent_flags",
+		Tables:           []string{"launchdarkly_feature_flag_prerequisite.prerequisite_dependent_flags"},
+		NeedsProject:     true,
+		NeedsEnvironment: true,
+		NeedsFlag:        true,
+		Probe: func(ctx context.Context, _ *ldapi.APIClient, beta *ldapi.APIClient, target *connectionHealthTarget) (*http.Response, error) {
+			_, resp, err := beta.FeatureFlagsBetaApi.GetDependentFlagsByEnv(ctx, target.ProjectKey, target.EnvironmentKey, target.FlagKey).Execute()
+			return resp, err
+		},
+	},
+	{
+		Api:              "segments",
+		Tables:           []string{"launchdarkly_big_segment_import_export", "launchdarkly_segment", "launchdarkly_segment_membership"},
+		NeedsProject:     true,
+		NeedsEnvironment: true,
+		Probe: func(ctx context.Context, client *ldapi.APIClient, _ *ldapi.APIClient, target *connectionHealthTarget) (*http.Response, error) {
+			_, resp, err := client.SegmentsApi.GetSegments(ctx, target.ProjectKey, target.EnvironmentKey).Execute()
+			return resp, err
+		},
+	},
+	{
+		Api:    "account_members",
+		Tables: []string{"launchdarkly_account_member", "launchdarkly_permission_check", "launchdarkly_policy_statement"},
+		Probe: func(ctx context.Context, client *ldapi.APIClient, _ *ldapi.APIClient, _ *connectionHealthTarget) (*http.Response, error) {
+			_, resp, err := client.AccountMembersApi.GetMembers(ctx).Limit(1).Execute()
+			return resp, err
+		},
+	},
+	{
+		Api:    "teams",
+		Tables: []string{"launchdarkly_team", "launchdarkly_policy_statement"},
+		Probe: func(ctx context.Context, client *ldapi.APIClient, _ *ldapi.APIClient, _ *connectionHealthTarget) (*http.Response, error) {
+			_, resp, err := client.TeamsApi.GetTeams(ctx).Limit(1).Execute()
+			return resp, err
+		},
+	},
+	{
+		Api:    "custom_roles",
+		Tables: []string{"launchdarkly_custom_role", "launchdarkly_permission_check", "launchdarkly_policy_statement"},
+		Probe: func(ctx context.Context, client *ldapi.APIClient, _ *ldapi.APIClient, _ *connectionHealthTarget) (*http.Response, error) {
+			_, resp, err := client.CustomRolesApi.GetCustomRoles(ctx).Execute()
+			return resp, err
+		},
+	},
+	{
+		Api:    "access_tokens",
+		Tables: []string{"launchdarkly_access_token", "launchdarkly_permission_check", "launchdarkly_policy_statement"},
+		Probe: func(ctx context.Context, client *ldapi.APIClient, _ *ldapi.APIClient, _ *connectionHealthTarget) (*http.Response, error) {
+			_, resp, err := client.AccessTokensApi.GetTokens(ctx).Execute()
+			return resp, err
+		},
+	},
+	{
+		Api:    "webhooks",
+		Tables: []string{"launchdarkly_policy_statement"},
+		Probe: func(ctx context.Context, client *ldapi.APIClient, _ *ldapi.APIClient, _ *connectionHealthTarget) (*http.Response, error) {
+			_, resp, err := client.WebhooksApi.GetAllWebhooks(ctx).Execute()
+			return resp, err
+		},
+	},
+	{
+		Api:    "audit_log",
+		Tables: []string{"launchdarkly_audit_log"},
+		Probe: func(ctx context.Context, client *ldapi.APIClient, _ *ldapi.APIClient, _ *connectionHealthTarget) (*http.Response, error) {
+			_, resp, err := client.AuditLogApi.GetAuditLogEntries(ctx).Limit(1).Execute()
+			return resp, err
+		},
+	},
+}
+
+//// LIST FUNCTION
+
+func listConnectionHealth(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	// Create client
+	client, err := connect(ctx, d)
+	if err != nil {
+		logger.Error("launchdarkly_connection_health.listConnectionHealth", "connection_error", err)
+		return nil, err
+	}
+	beta, err := connectBeta(ctx, d)
+	if err != nil {
+		logger.Error("launchdarkly_connection_health.listConnectionHealth", "connection_error", err)
+		return nil, err
+	}
+
+	// Probe a project the connection is restricted to, if any
+	target := &connectionHealthTarget{
+		ProjectPatterns:     GetConfig(d.Connection).Projects,
+		EnvironmentPatterns: GetConfig(d.Connection).Environments,
+	}
+	if projectKeys := scopedProjectKeys(d); projectKeys != nil {
+		target.ProjectKey = projectKeys[0]
+	}
+
+	for _, probe := range connectionHealthProbes {
+		row := launchdarklyConnectionHealth{
+			Api:    probe.Api,
+			Tables: probe.Tables,
+		}
+
+		if (probe.NeedsProject && target.ProjectKey == "") ||
+			(probe.NeedsEnvironment && target.EnvironmentKey == "") ||
+			(probe.NeedsFlag && target.FlagKey == "") {
+			row.Status = "skipped"
+		} else {
+			if probe.NeedsProject {
+				projectKey := target.ProjectKey
+				row.ProjectKey = &projectKey
+			}
+			if probe.NeedsEnvironment {
+				environmentKey := target.EnvironmentKey
+				row.EnvironmentKey = &environmentKey
+			}
+
+			start := time.Now()
+			resp, err := probe.Probe(ctx, client, beta, target)
+			latency := time.Since(start).Milliseconds()
+			row.LatencyMs = &latency
+
+			err = apiError(resp, err)
+			if statusCode := errorStatusCode(err); statusCode != 0 {
+				row.StatusCode = &statusCode
+			} else if resp != nil {
+				row.StatusCode = &resp.StatusCode
+			}
+
+			switch {
+			case err == nil:
+				row.Status = "reachable"
+			case errorStatusCode(err) == http.StatusForbidden:
+				row.Status = "forbidden"
+			default:
+				row.Status = "error"
+			}
+			if err != nil {
+				msg := err.Error()
+				row.Error = &msg
+			}
+		}
+
+		d.StreamListItem(ctx, row)
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,133 @@
+package launchdarkly
+
+import (
+	"context"
+	"net/http"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tablelaunchdarklyCustomRole(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "launchdarkly_custom_role",
+		Description: "Fetch a list of all custom roles.",
+		List: &plugin.ListConfig{
+			Hydrate: listCustomRoles,
+			RetryConfig: &plugin.RetryConfig{
+				ShouldRetryErrorFunc: shouldRetryError([]int{http.StatusTooManyRequests}),
+			},
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: plugin.SingleColumn("key"),
+			Hydrate:    getCustomRole,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "key",
+				Description: "The key of the custom role.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "name",
+				Description: "The name of the custom role.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "id",
+				Description: "The ID of the custom role.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "description",
+				Description: "The description of the custom role.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "base_permissions",
+				Description: "The permissions of the role before its policy is applied: reader or no_access.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "policy",
+				Description: "The policy statements of the custom role, each with an effect, actions or not_actions and resources or not_resources.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "access",
+				Description: "The actions the access token of the connection is allowed or denied on the custom role.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "links",
+				Description: "The location and content type of related resources.",
+				Type:        proto.ColumnType_JSON,
+			},
+			// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "Title of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Name"),
+			},
+		},
+	}
+}
+
+// maxCustomRolesPageSize is the page size used to list custom roles.
+const maxCustomRolesPageSize = 20
+
+//// LIST FUNCTION
+
+func listCustomRoles(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	// Create client
+	client, err := connect(ctx, d)
+	if err != nil {
+		logger.Error("launchdarkly_custom_role.listCustomRoles", "connection_error", err)
+		return nil, err
+	}
+
+	limit := int64(maxCustomRolesPageSize)
+	if d.QueryContext.Limit != nil && *d.QueryContext.Limit < limit {
+		limit = *d.QueryContext.Limit
+	}
+
+	err = getJSONPages(ctx, client, "/api/v2/roles", limit, func(role ldapi.CustomRole) bool {
+		d.StreamListItem(ctx, role)
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		return d.RowsRemaining(ctx) != 0
+	})
+	if err != nil {
+		logger.Error("launchdarkly_custom_role.listCustomRoles", "api_error", err)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getCustomRole(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	key := d.EqualsQualString("key")
+
+	// Create client
+	client, err := connect(ctx, d)
+	if err != nil {
+		logger.Error("launchdarkly_custom_role.getCustomRole", "connection_error", err)
+		return nil, err
+	}
+
+	role, resp, err := client.CustomRolesApi.GetCustomRole(ctx, key).Execute()
+	if err != nil {
+		err = apiError(resp, err)
+		logger.Error("launchdarkly_custom_role.getCustomRole", "api_error", err)
+		return nil, err
+	}
+
+	return *role, nil
+}
@@ -2,6 +2,7 @@ package launchdarkly
 
 import (
 	"context"
+	"net/http"
 
 	ldapi "github.com/launchdarkly/api-client-go/v13"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
@@ -18,8 +19,11 @@ func tablelaunchdarklyEnvironment(_ context.Context) *plugin.Table {
 		List: &plugin.ListConfig{
 			ParentHydrate: listProjects,
 			Hydrate:       listEnvironments,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "project_key", Require: plugin.Optional},
+			},
 			RetryConfig: &plugin.RetryConfig{
-				ShouldRetryErrorFunc: shouldRetryError([]string{"429"}),
+				ShouldRetryErrorFunc: shouldRetryError([]int{http.StatusTooManyRequests}),
 			},
 		},
 		Get: &plugin.GetConfig{
@@ -122,42 +126,75 @@ type launchdarklyProjectEnvironment struct {
 // LIST FUNCTION
 
 func listEnvironments(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
-	logger := plugin.Logger(ctx)
-
 	project := h.Item.(ldapi.Project)
 	projectKey := project.Key
 
-	// Create client
-	client, err := connect(ctx, d)
+	err := listProjectEnvironments(ctx, d, projectKey, func(environment ldapi.Environment) bool {
+		d.StreamListItem(ctx, launchdarklyProjectEnvironment{environment, projectKey})
+		return d.RowsRemaining(ctx) != 0
+	})
 	if err != nil {
-		logger.Error("launchdarkly_environment.listEnvironments", "connection_error", err)
+		plugin.Logger(ctx).Error("launchdarkly_environment.listEnvironments", "api_error", err)
 		return nil, err
 	}
+	return nil, nil
+}
+
+// listProjectEnvironments pages through the environments of a project and
+// passes each one to handler, stopping early when handler returns false.
+func listProjectEnvironments(ctx context.Context, d *plugin.QueryData, projectKey string, handler func(ldapi.Environment) bool) error {
+	client, err := connect(ctx, d)
+	if err != nil {
+		return err
+	}
 
 	params := client.EnvironmentsApi.GetEnvironmentsByProject(ctx, projectKey)
 
 	count := 0
 
 	for {
-		environments, _, err := params.Execute()
+		environments, resp, err := params.Execute()
 		if err != nil {
-			plugin.Logger(ctx).Error("launchdarkly_environment.listEnvironments", "api_error", err)
-			return nil, err
+			err = apiError(resp, err)
+			return err
 		}
 
 		for _, environment := range environments.Items {
-			d.StreamListItem(ctx, launchdarklyProjectEnvironment{environment, projectKey})
-			if d.RowsRemaining(ctx) == 0 {
-				return nil, nil
+			if !environmentInScope(d, environment.Key) {
+				continue
+			}
+			if !handler(environment) {
+				return nil
 			}
 		}
 		count += len(environments.Items)
 		if count >= int(environments.GetTotalCount()) {
 			break
 		}
-		params.Offset(int64(count))
+		params = params.Offset(int64(count))
 	}
-	return nil, nil
+	return nil
+}
+
+// projectEnvironmentKeys returns the keys of the environments of a project,
+// or only environmentKey if it is set and the connection can access it.
+func projectEnvironmentKeys(ctx context.Context, d *plugin.QueryData, projectKey string, environmentKey string) ([]string, error) {
+	if environmentKey != "" {
+		if !environmentInScope(d, environmentKey) {
+			return nil, nil
+		}
+		return []string{environmentKey}, nil
+	}
+
+	environmentKeys := []string{}
+	err := listProjectEnvironments(ctx, d, projectKey, func(environment ldapi.Environment) bool {
+		environmentKeys = append(environmentKeys, environment.Key)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return environmentKeys, nil
 }
 
 func getEnvironment(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
@@ -171,10 +208,14 @@ func getEnvironment(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateD
 
 	projectKey := d.EqualsQualString("project_key")
 	environmentKey := d.EqualsQualString("key")
+	if !projectInScope(d, projectKey) || !environmentInScope(d, environmentKey) {
+		return nil, nil
+	}
 
-	environment, _, err := client.EnvironmentsApi.GetEnvironment(ctx, projectKey, environmentKey).Execute()
+	environment, resp, err := client.EnvironmentsApi.GetEnvironment(ctx, projectKey, environmentKey).Execute()
 
 	if err != nil {
+		err = apiError(resp, err)
 		logger.Error("launchdarkly_environment.getEnvironment", "api_error", err)
 		return nil, err
 	}
@@ -2,6 +2,9 @@ package launchdarkly
 
 import (
 	"context"
+	"net/http"
+	"slices"
+	"strings"
 
 	ldapi "github.com/launchdarkly/api-client-go/v13"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
@@ -18,8 +21,22 @@ func tablelaunchdarklyFeatureFlag(_ context.Context) *plugin.Table {
 		List: &plugin.ListConfig{
 			ParentHydrate: listProjects,
 			Hydrate:       listFeatureFlags,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "project_key", Require: plugin.Optional},
+				{Name: "key", Require: plugin.Optional},
+				{Name: "kind", Require: plugin.Optional},
+				{Name: "tags", Require: plugin.Optional, Operators: []string{"?"}},
+				{Name: "maintainer_id", Require: plugin.Optional},
+				{Name: "maintainer_team_key", Require: plugin.Optional},
+				{Name: "archived", Require: plugin.Optional, Operators: []string{"=", "<>"}},
+				{Name: "temporary", Require: plugin.Optional, Operators: []string{"=", "<>"}},
+				{Name: "query", Require: plugin.Optional},
+				{Name: "state", Require: plugin.Optional},
+				{Name: "environment", Require: plugin.Optional},
+				{Name: "summary", Require: plugin.Optional},
+			},
 			RetryConfig: &plugin.RetryConfig{
-				ShouldRetryErrorFunc: shouldRetryError([]string{"429"}),
+				ShouldRetryErrorFunc: shouldRetryError([]int{http.StatusTooManyRequests}),
 			},
 		},
 		Get: &plugin.GetConfig{
@@ -94,6 +111,30 @@ func tablelaunchdarklyFeatureFlag(_ context.Context) *plugin.Table {
 				Description: "The key of this project.",
 				Type:        proto.ColumnType_STRING,
 			},
+			{
+				Name:        "query",
+				Description: "Text to search for in the key or name of the flag, ignoring case.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("query"),
+			},
+			{
+				Name:        "state",
+				Description: "The lifecycle state to filter flags by, such as live, deprecated or archived.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("state"),
+			},
+			{
+				Name:        "environment",
+				Description: "The key of the environment to restrict the environments column to.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("environment"),
+			},
+			{
+				Name:        "summary",
+				Description: "Set to false to include the prerequisites, targets and rules of each environment in the environments column.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromQual("summary"),
+			},
 			{
 				Name:        "variation",
 				Description: "An array of possible variations for the flag.",
@@ -166,39 +207,231 @@ func listFeatureFlags(ctx context.Context, d *plugin.QueryData, h *plugin.Hydrat
 	logger := plugin.Logger(ctx)
 
 	project := h.Item.(ldapi.Project)
-	projectKey := project.Key
+	opts := featureFlagListOptionsFromQuals(d)
+	kind := d.EqualsQualString("kind")
 
-	// Create client
-	client, err := connect(ctx, d)
+	handler := func(flag launchdarklyFeatureFlag) bool {
+		// The API cannot filter by kind
+		if kind != "" && kind != flag.Kind {
+			return true
+		}
+		d.StreamListItem(ctx, flag)
+		return d.RowsRemaining(ctx) != 0
+	}
+
+	// The API cannot fetch a flag by key with the filters of the other quals,
+	// so with both, list the filtered flags and keep those with the keys
+	if d.EqualsQuals["key"] != nil && len(opts.Filters) > 0 {
+		keys := getQualStringList(d, "key")
+		// Narrow the list to flags whose key or name contains the key
+		if len(keys) == 1 && d.EqualsQualString("query") == "" {
+			opts.Filters = append(opts.Filters, "query:"+keys[0])
+		}
+		filtered := handler
+		handler = func(flag launchdarklyFeatureFlag) bool {
+			if !slices.Contains(keys, flag.Key) {
+				return true
+			}
+			return filtered(flag)
+		}
+	} else if d.EqualsQuals["key"] != nil {
+		// Fetch each flag directly for key = and key in (...)
+		keys := getQualStringList(d, "key")
+		for _, key := range keys {
+			opts.Key = key
+			err := listProjectFeatureFlags(ctx, d, project.Key, opts, handler)
+			if err != nil && !isNotFoundError(err) {
+				logger.Error("launchdarkly_feature_flag.listFeatureFlags", "api_error", err)
+				return nil, err
+			}
+			if d.RowsRemaining(ctx) == 0 {
+				break
+			}
+		}
+		return nil, nil
+	}
+
+	err := listProjectFeatureFlags(ctx, d, project.Key, opts, handler)
 	if err != nil {
-		logger.Error("launchdarkly_feature_flag.listFeatureFlags", "connection_error", err)
+		logger.Error("launchdarkly_feature_flag.listFeatureFlags", "api_error", err)
 		return nil, err
 	}
+	return nil, nil
+}
+
+// featureFlagListOptionsFromQuals translates the quals of the
+// launchdarkly_feature_flag table into flag list parameters.
+func featureFlagListOptionsFromQuals(d *plugin.QueryData) featureFlagListOptions {
+	opts := featureFlagListOptions{}
+
+	if d.EqualsQualString("query") != "" {
+		opts.Filters = append(opts.Filters, "query:"+d.EqualsQualString("query"))
+	}
+	if d.EqualsQualString("state") != "" {
+		opts.Filters = append(opts.Filters, "state:"+d.EqualsQualString("state"))
+	}
+	if d.EqualsQualString("maintainer_id") != "" {
+		opts.Filters = append(opts.Filters, "maintainerId:"+d.EqualsQualString("maintainer_id"))
+	}
+	if d.EqualsQualString("maintainer_team_key") != "" {
+		opts.Filters = append(opts.Filters, "maintainerTeamKey:"+d.EqualsQualString("maintainer_team_key"))
+	}
+
+	// Flags must have all of the tags, e.g. tags ? 'beta' and tags ? 'test'
+	if d.Quals["tags"] != nil {
+		tags := []string{}
+		for _, q := range d.Quals["tags"].Quals {
+			if q.Value.GetStringValue() != "" {
+				tags = append(tags, q.Value.GetStringValue())
+			}
+		}
+		if len(tags) > 0 {
+			opts.Filters = append(opts.Filters, "tags:"+strings.Join(tags, "+"))
+		}
+	}
+
+	// Only unarchived flags are returned unless archived flags are requested
+	if d.Quals["archived"] != nil {
+		for _, q := range d.Quals["archived"].Quals {
+			archived := q.Value.GetBoolValue()
+			if q.Operator == "<>" {
+				archived = !archived
+			}
+			if archived {
+				opts.Filters = append(opts.Filters, "archived:true")
+			}
+		}
+	}
+
+	if d.Quals["temporary"] != nil {
+		for _, q := range d.Quals["temporary"].Quals {
+			temporary := q.Value.GetBoolValue()
+			if q.Operator == "<>" {
+				temporary = !temporary
+			}
+			if temporary {
+				opts.Filters = append(opts.Filters, "type:temporary")
+			} else {
+				opts.Filters = append(opts.Filters, "type:permanent")
+			}
+		}
+	}
+
+	if d.EqualsQualString("environment") != "" {
+		opts.Env = d.EqualsQualString("environment")
+		opts.Filters = append(opts.Filters, "filterEnv:"+opts.Env)
+	}
+
+	if d.EqualsQuals["summary"] != nil {
+		summary := d.EqualsQuals["summary"].GetBoolValue()
+		opts.Summary = &summary
+	}
+
+	return opts
+}
+
+// featureFlagListOptions narrows the flags, and the environment configurations
+// within them, requested from the API.
+type featureFlagListOptions struct {
+	// Key fetches a single flag instead of listing the project.
+	Key string
+	// Env restricts the environment configurations returned to one environment.
+	Env string
+	// Summary, when set, controls whether prerequisites, targets and rules are
+	// omitted from each environment configuration.
+	Summary *bool
+	// Filters are field:value pairs sent in the filter parameter.
+	Filters []string
+}
+
+// listProjectFeatureFlags pages through the flags of a project and passes each
+// one to handler, stopping early when handler returns false.
+func listProjectFeatureFlags(ctx context.Context, d *plugin.QueryData, projectKey string, opts featureFlagListOptions, handler func(launchdarklyFeatureFlag) bool) error {
+	client, err := connect(ctx, d)
+	if err != nil {
+		return err
+	}
+
+	if opts.Key != "" {
+		params := client.FeatureFlagsApi.GetFeatureFlag(ctx, projectKey, opts.Key)
+		if opts.Env != "" {
+			params = params.Env(opts.Env)
+		}
+		flag, resp, err := params.Execute()
+		if err != nil {
+			err = apiError(resp, err)
+			return err
+		}
+		scopeFeatureFlagEnvironments(d, flag)
+		handler(launchdarklyFeatureFlag{*flag, projectKey})
+		return nil
+	}
 
 	params := client.FeatureFlagsApi.GetFeatureFlags(ctx, projectKey)
+	if opts.Env != "" {
+		params = params.Env(opts.Env)
+	}
+	if opts.Summary != nil {
+		params = params.Summary(*opts.Summary)
+	}
+	if len(opts.Filters) > 0 {
+		params = params.Filter(strings.Join(opts.Filters, ","))
+	}
 
 	count := 0
 
 	for {
-		flags, _, err := params.Execute()
+		flags, resp, err := params.Execute()
 		if err != nil {
-			plugin.Logger(ctx).Error("launchdarkly_feature_flag.listFeatureFlags", "api_error", err)
-			return nil, err
+			err = apiError(resp, err)
+			return err
 		}
 
 		for _, flag := range flags.Items {
-			d.StreamListItem(ctx, launchdarklyFeatureFlag{flag, projectKey})
-			if d.RowsRemaining(ctx) == 0 {
-				return nil, nil
+			scopeFeatureFlagEnvironments(d, &flag)
+			if !handler(launchdarklyFeatureFlag{flag, projectKey}) {
+				return nil
 			}
 		}
 		count += len(flags.Items)
-		if count >= int(flags.GetTotalCount()) {
+		if len(flags.Items) == 0 || count >= int(flags.GetTotalCount()) {
 			break
 		}
-		params.Offset(int64(count))
+		params = params.Offset(int64(count))
 	}
-	return nil, nil
+	return nil
+}
+
+// featureFlagConfigHandler is called with a flag and one of its environment
+// configurations. Returning false stops the listing.
+type featureFlagConfigHandler func(flag launchdarklyFeatureFlag, environmentKey string, config ldapi.FeatureFlagConfig) bool
+
+// listFeatureFlagConfigs lists the full environment configurations, including
+// targets, rules and prerequisites, of the flags in a project. flagKey and
+// environmentKey are sent to the API when set.
+func listFeatureFlagConfigs(ctx context.Context, d *plugin.QueryData, projectKey string, flagKey string, environmentKey string, handler featureFlagConfigHandler) error {
+	if environmentKey != "" && !environmentInScope(d, environmentKey) {
+		return nil
+	}
+
+	summary := false
+	opts := featureFlagListOptions{
+		Key:     flagKey,
+		Env:     environmentKey,
+		Summary: &summary,
+	}
+
+	return listProjectFeatureFlags(ctx, d, projectKey, opts, func(flag launchdarklyFeatureFlag) bool {
+		for key, config := range flag.Environments {
+			if environmentKey != "" && environmentKey != key {
+				continue
+			}
+			if !handler(flag, key, config) {
+				return false
+			}
+		}
+		return true
+	})
 }
 
 func getFeatureFlag(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
@@ -212,13 +445,18 @@ func getFeatureFlag(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateD
 
 	projectKey := d.EqualsQualString("project_key")
 	featureFlagKey := d.EqualsQualString("key")
+	if !projectInScope(d, projectKey) {
+		return nil, nil
+	}
 
-	flag, _, err := client.FeatureFlagsApi.GetFeatureFlag(ctx, projectKey, featureFlagKey).Execute()
+	flag, resp, err := client.FeatureFlagsApi.GetFeatureFlag(ctx, projectKey, featureFlagKey).Execute()
 
 	if err != nil {
+		err = apiError(resp, err)
 		logger.Error("launchdarkly_feature_flag.getFeatureFlag", "api_error", err)
 		return nil, err
 	}
+	scopeFeatureFlagEnvironments(d, flag)
 
 	return launchdarklyFeatureFlag{*flag, projectKey}, nil
 }
@@ -0,0 +1,196 @@
+package launchdarkly
+
+import (
+	"context"
+	"net/http"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tablelaunchdarklyFeatureFlagEnvironment(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "launchdarkly_feature_flag_environment",
+		Description: "Fetch the configuration of each feature flag in each environment.",
+		List: &plugin.ListConfig{
+			ParentHydrate: listProjects,
+			Hydrate:       listFeatureFlagEnvironments,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "project_key", Require: plugin.Optional},
+				{Name: "environment_key", Require: plugin.Optional},
+				{Name: "key", Require: plugin.Optional},
+			},
+			RetryConfig: &plugin.RetryConfig{
+				ShouldRetryErrorFunc: shouldRetryError([]int{http.StatusTooManyRequests}),
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "key",
+				Description: "The key of the feature flag.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "name",
+				Description: "A human-friendly name for the feature flag.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "environment_key",
+				Description: "The key of the environment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "environment_name",
+				Description: "The name of the environment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "on",
+				Description: "Whether the flag is on in the environment.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "archived",
+				Description: "Boolean indicating if the feature flag is archived.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "version",
+				Description: "Version of the flag configuration in the environment.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "last_modified",
+				Description: "Time when the flag configuration was last modified in the environment.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("LastModified").Transform(transform.UnixMsToTimestamp),
+			},
+			{
+				Name:        "off_variation",
+				Description: "The index of the variation to serve when the flag is off.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "fallthrough_variation",
+				Description: "The index of the variation served by the default rule, if the default rule serves a fixed variation.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("Fallthrough.Variation"),
+			},
+			{
+				Name:        "fallthrough_rollout",
+				Description: "The percentage rollout served by the default rule, if the default rule serves a rollout.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Fallthrough.Rollout"),
+			},
+			{
+				Name:        "track_events",
+				Description: "Whether LaunchDarkly tracks events for the feature flag, for all rules.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "track_events_fallthrough",
+				Description: "Whether LaunchDarkly tracks events for the feature flag, for the default rule.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "salt",
+				Description: "The salt used to bucket contexts into rollouts.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "targets_count",
+				Description: "The number of user keys individually targeted.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "context_targets_count",
+				Description: "The number of context keys of other context kinds individually targeted.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "rules_count",
+				Description: "The number of targeting rules.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "prerequisites_count",
+				Description: "The number of prerequisite flags.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "project_key",
+				Description: "The key of the project.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "site",
+				Description: "The location and content type of the flag configuration in the LaunchDarkly UI.",
+				Type:        proto.ColumnType_JSON,
+			},
+			// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "Title of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Name"),
+			},
+		},
+	}
+}
+
+type launchdarklyFeatureFlagEnvironment struct {
+	ldapi.FeatureFlagConfig
+	Key                 string
+	Name                string
+	ProjectKey          string
+	EnvironmentKey      string
+	TargetsCount        int
+	ContextTargetsCount int
+	RulesCount          int
+	PrerequisitesCount  int
+}
+
+// flagTargetsCount returns the number of context keys of flag targets, which
+// group context keys by variation and context kind.
+func flagTargetsCount(targets []ldapi.Target) int {
+	count := 0
+	for _, target := range targets {
+		count += len(target.Values)
+	}
+	return count
+}
+
+// LIST FUNCTION
+
+func listFeatureFlagEnvironments(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	project := h.Item.(ldapi.Project)
+	projectKey := project.Key
+
+	flagKey := d.EqualsQualString("key")
+	environmentKey := d.EqualsQualString("environment_key")
+
+	err := listFeatureFlagConfigs(ctx, d, projectKey, flagKey, environmentKey, func(flag launchdarklyFeatureFlag, environmentKey string, config ldapi.FeatureFlagConfig) bool {
+		d.StreamListItem(ctx, launchdarklyFeatureFlagEnvironment{
+			FeatureFlagConfig:   config,
+			Key:                 flag.Key,
+			Name:                flag.Name,
+			ProjectKey:          projectKey,
+			EnvironmentKey:      environmentKey,
+			TargetsCount:        flagTargetsCount(config.Targets),
+			ContextTargetsCount: flagTargetsCount(config.ContextTargets),
+			RulesCount:          len(config.Rules),
+			PrerequisitesCount:  len(config.Prerequisites),
+		})
+		return d.RowsRemaining(ctx) != 0
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error("launchdarkly_feature_flag_environment.listFeatureFlagEnvironments", "api_error", err)
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,315 @@
+package launchdarkly
+
+import (
+	"context"
+	"net/http"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tablelaunchdarklyFeatureFlagPrerequisite(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "launchdarkly_feature_flag_prerequisite",
+		Description: "Fetch the prerequisite flags of each feature flag in each environment.",
+		List: &plugin.ListConfig{
+			ParentHydrate: listProjects,
+			Hydrate:       listFeatureFlagPrerequisites,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "project_key", Require: plugin.Optional},
+				{Name: "environment_key", Require: plugin.Optional},
+				{Name: "flag_key", Require: plugin.Optional},
+				{Name: "prerequisite_key", Require: plugin.Optional},
+			},
+			RetryConfig: &plugin.RetryConfig{
+				ShouldRetryErrorFunc: shouldRetryError([]int{http.StatusTooManyRequests}),
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "flag_key",
+				Description: "The key of the flag that has the prerequisite.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "prerequisite_key",
+				Description: "The key of the prerequisite flag.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "variation",
+				Description: "The index of the variation the prerequisite flag must serve for the flag to take effect.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "environment_key",
+				Description: "The key of the environment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "flag_on",
+				Description: "Whether the flag that has the prerequisite is on in the environment.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "prerequisite_exists",
+				Description: "Whether the prerequisite flag exists in the project.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "prerequisite_on",
+				Description: "Whether the prerequisite flag is on in the environment.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "prerequisite_archived",
+				Description: "Whether the prerequisite flag is archived.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "depth",
+				Description: "The number of flags in the longest prerequisite chain starting at this prerequisite, e.g. 1 if the prerequisite flag has no prerequisites of its own.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "in_cycle",
+				Description: "Whether the prerequisite flag depends, directly or transitively, on the flag that has the prerequisite.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "project_key",
+				Description: "The key of the project.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "prerequisite_dependent_flags",
+				Description: "The flags that use the prerequisite flag as a prerequisite in the environment, as reported by the LaunchDarkly dependent flags API. Requires flag prerequisites, an Enterprise feature.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getFeatureFlagPrerequisiteDependentFlags,
+				Transform:   transform.FromValue(),
+			},
+			// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "Title of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PrerequisiteKey"),
+			},
+		},
+	}
+}
+
+type launchdarklyFeatureFlagPrerequisite struct {
+	FlagKey              string
+	PrerequisiteKey      string
+	Variation            int32
+	ProjectKey           string
+	EnvironmentKey       string
+	FlagOn               bool
+	PrerequisiteExists   bool
+	PrerequisiteOn       *bool
+	PrerequisiteArchived *bool
+	Depth                int
+	InCycle              bool
+}
+
+// prerequisiteGraph holds the prerequisites of every flag of a project in a
+// single environment.
+type prerequisiteGraph struct {
+	configs       map[string]ldapi.FeatureFlagConfig
+	archived      map[string]bool
+	prerequisites map[string][]ldapi.Prerequisite
+}
+
+// LIST FUNCTION
+
+func listFeatureFlagPrerequisites(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	project := h.Item.(ldapi.Project)
+	projectKey := project.Key
+
+	environmentKey := d.EqualsQualString("environment_key")
+	flagKey := d.EqualsQualString("flag_key")
+	prerequisiteKey := d.EqualsQualString("prerequisite_key")
+
+	// Prerequisite chains can span every flag in the project, so only the
+	// environment is sent to the API and the flag quals are applied afterwards
+	graphs := map[string]*prerequisiteGraph{}
+	err := listFeatureFlagConfigs(ctx, d, projectKey, "", environmentKey, func(flag launchdarklyFeatureFlag, environmentKey string, config ldapi.FeatureFlagConfig) bool {
+		graph, ok := graphs[environmentKey]
+		if !ok {
+			graph = &prerequisiteGraph{
+				configs:       map[string]ldapi.FeatureFlagConfig{},
+				archived:      map[string]bool{},
+				prerequisites: map[string][]ldapi.Prerequisite{},
+			}
+			graphs[environmentKey] = graph
+		}
+		graph.configs[flag.Key] = config
+		graph.archived[flag.Key] = flag.Archived
+		graph.prerequisites[flag.Key] = config.Prerequisites
+		return true
+	})
+	if err != nil {
+		logger.Error("launchdarkly_feature_flag_prerequisite.listFeatureFlagPrerequisites", "api_error", err)
+		return nil, err
+	}
+
+	// Archived flags are not listed, so prerequisites missing from the listing
+	// are fetched individually, once per project
+	missingFlags := map[string]*ldapi.FeatureFlag{}
+
+	for environmentKey, graph := range graphs {
+		depths := map[string]int{}
+		for key, prerequisites := range graph.prerequisites {
+			if flagKey != "" && flagKey != key {
+				continue
+			}
+			for _, prerequisite := range prerequisites {
+				if prerequisiteKey != "" && prerequisiteKey != prerequisite.Key {
+					continue
+				}
+
+				row := launchdarklyFeatureFlagPrerequisite{
+					FlagKey:         key,
+					PrerequisiteKey: prerequisite.Key,
+					Variation:       prerequisite.Variation,
+					ProjectKey:      projectKey,
+					EnvironmentKey:  environmentKey,
+					FlagOn:          graph.configs[key].On,
+					InCycle:         graph.reaches(prerequisite.Key, key, map[string]bool{}),
+				}
+
+				depth, _ := graph.depth(prerequisite.Key, map[string]bool{key: true}, depths)
+				row.Depth = depth + 1
+
+				if config, ok := graph.configs[prerequisite.Key]; ok {
+					archived := graph.archived[prerequisite.Key]
+					row.PrerequisiteExists = true
+					row.PrerequisiteOn = &config.On
+					row.PrerequisiteArchived = &archived
+				} else {
+					flag, ok := missingFlags[prerequisite.Key]
+					if !ok {
+						flag, err = getPrerequisiteFlag(ctx, d, projectKey, prerequisite.Key)
+						if err != nil {
+							logger.Error("launchdarkly_feature_flag_prerequisite.listFeatureFlagPrerequisites", "api_error", err)
+							return nil, err
+						}
+						missingFlags[prerequisite.Key] = flag
+					}
+					if flag != nil {
+						row.PrerequisiteExists = true
+						row.PrerequisiteArchived = &flag.Archived
+						if config, ok := flag.Environments[environmentKey]; ok {
+							row.PrerequisiteOn = &config.On
+						}
+					}
+				}
+
+				d.StreamListItem(ctx, row)
+				if d.RowsRemaining(ctx) == 0 {
+					return nil, nil
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// getPrerequisiteFlag fetches a flag that was not part of the flag listing,
+// returning nil if it does not exist.
+func getPrerequisiteFlag(ctx context.Context, d *plugin.QueryData, projectKey string, key string) (*ldapi.FeatureFlag, error) {
+	client, err := connect(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	flag, resp, err := client.FeatureFlagsApi.GetFeatureFlag(ctx, projectKey, key).Execute()
+	if err != nil {
+		err = apiError(resp, err)
+		if isNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return flag, nil
+}
+
+// depth returns the number of flags in the longest prerequisite chain below
+// key. Flags already on the current path are skipped so that cycles terminate,
+// and depths affected by a skipped flag are not cached as they depend on the
+// path taken.
+func (g *prerequisiteGraph) depth(key string, path map[string]bool, depths map[string]int) (int, bool) {
+	if depth, ok := depths[key]; ok {
+		return depth, false
+	}
+
+	path[key] = true
+	defer delete(path, key)
+
+	depth := 0
+	cyclic := false
+	for _, prerequisite := range g.prerequisites[key] {
+		if path[prerequisite.Key] {
+			cyclic = true
+			continue
+		}
+		d, c := g.depth(prerequisite.Key, path, depths)
+		if d+1 > depth {
+			depth = d + 1
+		}
+		cyclic = cyclic || c
+	}
+
+	if !cyclic {
+		depths[key] = depth
+	}
+	return depth, cyclic
+}
+
+// reaches reports whether target is a direct or transitive prerequisite of key.
+func (g *prerequisiteGraph) reaches(key string, target string, visited map[string]bool) bool {
+	if key == target {
+		return true
+	}
+	if visited[key] {
+		return false
+	}
+	visited[key] = true
+
+	for _, prerequisite := range g.prerequisites[key] {
+		if g.reaches(prerequisite.Key, target, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+//// HYDRATE FUNCTIONS
+
+func getFeatureFlagPrerequisiteDependentFlags(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	prerequisite := h.Item.(launchdarklyFeatureFlagPrerequisite)
+
+	// Create client
+	client, err := connectBeta(ctx, d)
+	if err != nil {
+		logger.Error("launchdarkly_feature_flag_prerequisite.getFeatureFlagPrerequisiteDependentFlags", "connection_error", err)
+		return nil, err
+	}
+
+	dependents, resp, err := client.FeatureFlagsBetaApi.GetDependentFlagsByEnv(ctx, prerequisite.ProjectKey, prerequisite.EnvironmentKey, prerequisite.PrerequisiteKey).Execute()
+	if err != nil {
+		err = apiError(resp, err)
+		logger.Error("launchdarkly_feature_flag_prerequisite.getFeatureFlagPrerequisiteDependentFlags", "api_error", err)
+		return nil, err
+	}
+
+	return dependents.Items, nil
+}
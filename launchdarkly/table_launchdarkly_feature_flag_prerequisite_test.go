@@ -0,0 +1,121 @@
+package launchdarkly
+
+import (
+	"testing"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+)
+
+// newTestPrerequisiteGraph returns a graph in which each flag has the given
+// prerequisites.
+func newTestPrerequisiteGraph(edges map[string][]string) *prerequisiteGraph {
+	graph := &prerequisiteGraph{
+		configs:       map[string]ldapi.FeatureFlagConfig{},
+		archived:      map[string]bool{},
+		prerequisites: map[string][]ldapi.Prerequisite{},
+	}
+	for key, prerequisiteKeys := range edges {
+		for _, prerequisiteKey := range prerequisiteKeys {
+			graph.prerequisites[key] = append(graph.prerequisites[key], ldapi.Prerequisite{Key: prerequisiteKey})
+		}
+	}
+	return graph
+}
+
+func TestPrerequisiteGraphDepth(t *testing.T) {
+	chain := map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"d"}}
+	diamond := map[string][]string{"a": {"b", "c"}, "b": {"d"}, "c": {"d"}, "d": {"e"}}
+	cycle := map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}, "x": {"a"}}
+
+	tests := []struct {
+		name       string
+		edges      map[string][]string
+		key        string
+		path       []string
+		wantDepth  int
+		wantCyclic bool
+	}{
+		{name: "chain", edges: chain, key: "a", wantDepth: 3},
+		{name: "chain middle", edges: chain, key: "b", wantDepth: 2},
+		{name: "chain end", edges: chain, key: "d", wantDepth: 0},
+		{name: "missing flag", edges: chain, key: "z", wantDepth: 0},
+		{name: "diamond", edges: diamond, key: "a", wantDepth: 3},
+		{name: "diamond side", edges: diamond, key: "c", wantDepth: 2},
+		{name: "cycle", edges: cycle, key: "a", wantDepth: 2, wantCyclic: true},
+		{name: "cycle from dependent", edges: cycle, key: "b", path: []string{"a"}, wantDepth: 1, wantCyclic: true},
+		{name: "into cycle", edges: cycle, key: "x", wantDepth: 3, wantCyclic: true},
+		{name: "self", edges: map[string][]string{"a": {"a"}}, key: "a", wantDepth: 0, wantCyclic: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := newTestPrerequisiteGraph(tt.edges)
+			path := map[string]bool{}
+			for _, key := range tt.path {
+				path[key] = true
+			}
+
+			depth, cyclic := graph.depth(tt.key, path, map[string]int{})
+			if depth != tt.wantDepth || cyclic != tt.wantCyclic {
+				t.Errorf("depth(%q) = %d, %v, want %d, %v", tt.key, depth, cyclic, tt.wantDepth, tt.wantCyclic)
+			}
+			if len(path) != len(tt.path) {
+				t.Errorf("path has %d flags after depth, want %d", len(path), len(tt.path))
+			}
+		})
+	}
+}
+
+// TestPrerequisiteGraphDepthCache checks that depths shared between the rows
+// of a graph match the depths computed without a cache.
+func TestPrerequisiteGraphDepthCache(t *testing.T) {
+	for name, edges := range map[string]map[string][]string{
+		"diamond": {"a": {"b", "c"}, "b": {"d"}, "c": {"d"}, "d": {"e"}},
+		"cycle":   {"a": {"b"}, "b": {"c"}, "c": {"a"}, "x": {"a"}, "y": {"c"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			graph := newTestPrerequisiteGraph(edges)
+			depths := map[string]int{}
+			for key, prerequisites := range graph.prerequisites {
+				for _, prerequisite := range prerequisites {
+					cached, _ := graph.depth(prerequisite.Key, map[string]bool{key: true}, depths)
+					uncached, _ := graph.depth(prerequisite.Key, map[string]bool{key: true}, map[string]int{})
+					if cached != uncached {
+						t.Errorf("depth of %q below %q = %d with cache, want %d", prerequisite.Key, key, cached, uncached)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPrerequisiteGraphReaches(t *testing.T) {
+	diamond := map[string][]string{"a": {"b", "c"}, "b": {"d"}, "c": {"d"}}
+	cycle := map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}, "x": {"a"}}
+
+	tests := []struct {
+		name   string
+		edges  map[string][]string
+		key    string
+		target string
+		want   bool
+	}{
+		{name: "direct", edges: diamond, key: "a", target: "b", want: true},
+		{name: "transitive", edges: diamond, key: "a", target: "d", want: true},
+		{name: "reverse", edges: diamond, key: "d", target: "a", want: false},
+		{name: "sibling", edges: diamond, key: "b", target: "c", want: false},
+		{name: "cycle", edges: cycle, key: "b", target: "a", want: true},
+		{name: "into cycle", edges: cycle, key: "x", target: "c", want: true},
+		{name: "out of cycle", edges: cycle, key: "a", target: "x", want: false},
+		{name: "self", edges: map[string][]string{"a": {"a"}}, key: "a", target: "a", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := newTestPrerequisiteGraph(tt.edges)
+			if got := graph.reaches(tt.key, tt.target, map[string]bool{}); got != tt.want {
+				t.Errorf("reaches(%q, %q) = %v, want %v", tt.key, tt.target, got, tt.want)
+			}
+		})
+	}
+}
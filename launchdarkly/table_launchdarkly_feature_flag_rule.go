@@ -0,0 +1,153 @@
+package launchdarkly
+
+import (
+	"context"
+	"net/http"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tablelaunchdarklyFeatureFlagRule(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "launchdarkly_feature_flag_rule",
+		Description: "Fetch the targeting rules of each feature flag in each environment.",
+		List: &plugin.ListConfig{
+			ParentHydrate: listProjects,
+			Hydrate:       listFeatureFlagRules,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "project_key", Require: plugin.Optional},
+				{Name: "environment_key", Require: plugin.Optional},
+				{Name: "flag_key", Require: plugin.Optional},
+			},
+			RetryConfig: &plugin.RetryConfig{
+				ShouldRetryErrorFunc: shouldRetryError([]int{http.StatusTooManyRequests}),
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "id",
+				Description: "The ID of the rule.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "description",
+				Description: "The description of the rule.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "rule_index",
+				Description: "The position of the rule in the flag's list of rules, starting at 0. Rules are evaluated in this order.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "flag_key",
+				Description: "The key of the feature flag.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "environment_key",
+				Description: "The key of the environment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "variation",
+				Description: "The index of the variation served by the rule, if the rule serves a fixed variation.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "track_events",
+				Description: "Whether LaunchDarkly tracks events for this rule.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "ref",
+				Description: "A reference to the rule.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "clauses_count",
+				Description: "The number of clauses in the rule.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "project_key",
+				Description: "The key of the project.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "rollout",
+				Description: "The percentage rollout served by the rule, if the rule serves a rollout.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "clauses",
+				Description: "The clauses a context must match for the rule to apply.",
+				Type:        proto.ColumnType_JSON,
+			},
+			// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "Title of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(featureFlagRuleTitle),
+			},
+		},
+	}
+}
+
+type launchdarklyFeatureFlagRule struct {
+	ldapi.Rule
+	RuleIndex      int
+	FlagKey        string
+	ProjectKey     string
+	EnvironmentKey string
+	ClausesCount   int
+}
+
+// LIST FUNCTION
+
+func listFeatureFlagRules(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	project := h.Item.(ldapi.Project)
+	projectKey := project.Key
+
+	flagKey := d.EqualsQualString("flag_key")
+	environmentKey := d.EqualsQualString("environment_key")
+
+	err := listFeatureFlagConfigs(ctx, d, projectKey, flagKey, environmentKey, func(flag launchdarklyFeatureFlag, environmentKey string, config ldapi.FeatureFlagConfig) bool {
+		for i, rule := range config.Rules {
+			d.StreamListItem(ctx, launchdarklyFeatureFlagRule{
+				Rule:           rule,
+				RuleIndex:      i,
+				FlagKey:        flag.Key,
+				ProjectKey:     projectKey,
+				EnvironmentKey: environmentKey,
+				ClausesCount:   len(rule.Clauses),
+			})
+			if d.RowsRemaining(ctx) == 0 {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error("launchdarkly_feature_flag_rule.listFeatureFlagRules", "api_error", err)
+		return nil, err
+	}
+	return nil, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+// Rules do not always have a description, so fall back to the rule ID
+func featureFlagRuleTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	rule := d.HydrateItem.(launchdarklyFeatureFlagRule)
+	if rule.Description != nil && *rule.Description != "" {
+		return *rule.Description, nil
+	}
+	return rule.GetId(), nil
+}
@@ -0,0 +1,146 @@
+package launchdarkly
+
+import (
+	"context"
+	"net/http"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tablelaunchdarklyFeatureFlagRuleClause(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "launchdarkly_feature_flag_rule_clause",
+		Description: "Fetch the clauses of each feature flag targeting rule in each environment.",
+		List: &plugin.ListConfig{
+			ParentHydrate: listProjects,
+			Hydrate:       listFeatureFlagRuleClauses,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "project_key", Require: plugin.Optional},
+				{Name: "environment_key", Require: plugin.Optional},
+				{Name: "flag_key", Require: plugin.Optional},
+			},
+			RetryConfig: &plugin.RetryConfig{
+				ShouldRetryErrorFunc: shouldRetryError([]int{http.StatusTooManyRequests}),
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "id",
+				Description: "The ID of the clause.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "attribute",
+				Description: "The context attribute the clause evaluates, or segmentMatch for segment clauses.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "context_kind",
+				Description: "The context kind the attribute belongs to.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "op",
+				Description: "The operator used to compare the attribute with the values, such as in, endsWith or segmentMatch.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "negate",
+				Description: "Whether the result of the operator is negated.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "clause_index",
+				Description: "The position of the clause in the rule, starting at 0.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "rule_id",
+				Description: "The ID of the rule the clause belongs to.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "rule_index",
+				Description: "The position of the rule in the flag's list of rules, starting at 0.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "flag_key",
+				Description: "The key of the feature flag.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "environment_key",
+				Description: "The key of the environment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "project_key",
+				Description: "The key of the project.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "values",
+				Description: "The values the attribute is compared with. For segment clauses these are segment keys.",
+				Type:        proto.ColumnType_JSON,
+			},
+			// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "Title of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Attribute"),
+			},
+		},
+	}
+}
+
+type launchdarklyFeatureFlagRuleClause struct {
+	ldapi.Clause
+	ClauseIndex    int
+	RuleId         string
+	RuleIndex      int
+	FlagKey        string
+	ProjectKey     string
+	EnvironmentKey string
+}
+
+// LIST FUNCTION
+
+func listFeatureFlagRuleClauses(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	project := h.Item.(ldapi.Project)
+	projectKey := project.Key
+
+	flagKey := d.EqualsQualString("flag_key")
+	environmentKey := d.EqualsQualString("environment_key")
+
+	err := listFeatureFlagConfigs(ctx, d, projectKey, flagKey, environmentKey, func(flag launchdarklyFeatureFlag, environmentKey string, config ldapi.FeatureFlagConfig) bool {
+		for i, rule := range config.Rules {
+			for j, clause := range rule.Clauses {
+				d.StreamListItem(ctx, launchdarklyFeatureFlagRuleClause{
+					Clause:         clause,
+					ClauseIndex:    j,
+					RuleId:         rule.GetId(),
+					RuleIndex:      i,
+					FlagKey:        flag.Key,
+					ProjectKey:     projectKey,
+					EnvironmentKey: environmentKey,
+				})
+				if d.RowsRemaining(ctx) == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error("launchdarkly_feature_flag_rule_clause.listFeatureFlagRuleClauses", "api_error", err)
+		return nil, err
+	}
+	return nil, nil
+}
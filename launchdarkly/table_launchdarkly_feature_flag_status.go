@@ -0,0 +1,178 @@
+package launchdarkly
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"time"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tablelaunchdarklyFeatureFlagStatus(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "launchdarkly_feature_flag_status",
+		Description: "Fetch the evaluation status of each feature flag in each environment.",
+		List: &plugin.ListConfig{
+			ParentHydrate: listProjects,
+			Hydrate:       listFeatureFlagStatuses,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "project_key", Require: plugin.Optional},
+				{Name: "environment_key", Require: plugin.Optional},
+				{Name: "key", Require: plugin.Optional},
+			},
+			RetryConfig: &plugin.RetryConfig{
+				ShouldRetryErrorFunc: shouldRetryError([]int{http.StatusTooManyRequests}),
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "key",
+				Description: "The key of the feature flag.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "environment_key",
+				Description: "The key of the environment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "name",
+				Description: "The status of the flag in the environment: new, active, inactive or launched.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "last_requested",
+				Description: "Time when the flag was last evaluated by an SDK in the environment.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "project_key",
+				Description: "The key of the project.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "default",
+				Description: "The fallback value SDKs were last seen serving for the flag in code.",
+				Type:        proto.ColumnType_JSON,
+			},
+			// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "Title of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Key"),
+			},
+		},
+	}
+}
+
+type launchdarklyFeatureFlagStatus struct {
+	Key            string
+	Name           string
+	LastRequested  *time.Time
+	Default        interface{}
+	ProjectKey     string
+	EnvironmentKey string
+}
+
+// LIST FUNCTION
+
+func listFeatureFlagStatuses(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	project := h.Item.(ldapi.Project)
+	projectKey := project.Key
+
+	// Create client
+	client, err := connect(ctx, d)
+	if err != nil {
+		logger.Error("launchdarkly_feature_flag_status.listFeatureFlagStatuses", "connection_error", err)
+		return nil, err
+	}
+
+	flagKey := d.EqualsQualString("key")
+	environmentKey := d.EqualsQualString("environment_key")
+	if environmentKey != "" && !environmentInScope(d, environmentKey) {
+		return nil, nil
+	}
+
+	// A single flag can be fetched across every environment in one call
+	if flagKey != "" {
+		params := client.FeatureFlagsApi.GetFeatureFlagStatusAcrossEnvironments(ctx, projectKey, flagKey)
+		if environmentKey != "" {
+			params = params.Env(environmentKey)
+		}
+		statuses, resp, err := params.Execute()
+		if err != nil {
+			err = apiError(resp, err)
+			logger.Error("launchdarkly_feature_flag_status.listFeatureFlagStatuses", "api_error", err)
+			return nil, err
+		}
+
+		for key, status := range statuses.GetEnvironments() {
+			if (environmentKey != "" && environmentKey != key) || !environmentInScope(d, key) {
+				continue
+			}
+			d.StreamListItem(ctx, launchdarklyFeatureFlagStatus{
+				Key:            flagKey,
+				Name:           status.Name,
+				LastRequested:  status.LastRequested,
+				Default:        status.Default,
+				ProjectKey:     projectKey,
+				EnvironmentKey: key,
+			})
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+		return nil, nil
+	}
+
+	environmentKeys, err := projectEnvironmentKeys(ctx, d, projectKey, environmentKey)
+	if err != nil {
+		logger.Error("launchdarkly_feature_flag_status.listFeatureFlagStatuses", "api_error", err)
+		return nil, err
+	}
+
+	for _, key := range environmentKeys {
+		statuses, resp, err := client.FeatureFlagsApi.GetFeatureFlagStatuses(ctx, projectKey, key).Execute()
+		if err != nil {
+			err = apiError(resp, err)
+			logger.Error("launchdarkly_feature_flag_status.listFeatureFlagStatuses", "api_error", err)
+			return nil, err
+		}
+
+		for _, status := range statuses.Items {
+			d.StreamListItem(ctx, launchdarklyFeatureFlagStatus{
+				Key:            flagStatusKey(status),
+				Name:           status.GetName(),
+				LastRequested:  status.LastRequested,
+				Default:        status.Default,
+				ProjectKey:     projectKey,
+				EnvironmentKey: key,
+			})
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// flagStatusKey returns the flag key of a flag status, which the API only
+// includes as the last segment of the status links,
+// e.g. /api/v2/flag-statuses/{projectKey}/{environmentKey}/{flagKey}
+func flagStatusKey(status ldapi.FlagStatusRep) string {
+	for _, rel := range []string{"self", "parent"} {
+		if link, ok := status.Links[rel]; ok && link.GetHref() != "" {
+			return path.Base(link.GetHref())
+		}
+	}
+	return ""
+}
@@ -0,0 +1,151 @@
+package launchdarkly
+
+import (
+	"context"
+	"net/http"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tablelaunchdarklyFeatureFlagTarget(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "launchdarkly_feature_flag_target",
+		Description: "Fetch the contexts individually targeted by each feature flag in each environment.",
+		List: &plugin.ListConfig{
+			ParentHydrate: listProjects,
+			Hydrate:       listFeatureFlagTargets,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "project_key", Require: plugin.Optional},
+				{Name: "environment_key", Require: plugin.Optional},
+				{Name: "flag_key", Require: plugin.Optional},
+				{Name: "context_kind", Require: plugin.Optional},
+				{Name: "context_key", Require: plugin.Optional},
+			},
+			RetryConfig: &plugin.RetryConfig{
+				ShouldRetryErrorFunc: shouldRetryError([]int{http.StatusTooManyRequests}),
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "context_key",
+				Description: "The key of the individually targeted context.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "context_kind",
+				Description: "The kind of the individually targeted context.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "variation",
+				Description: "The index of the variation served to the context.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "variation_name",
+				Description: "The name of the variation served to the context.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "flag_key",
+				Description: "The key of the feature flag.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "environment_key",
+				Description: "The key of the environment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "project_key",
+				Description: "The key of the project.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "variation_value",
+				Description: "The value of the variation served to the context.",
+				Type:        proto.ColumnType_JSON,
+			},
+			// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "Title of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ContextKey"),
+			},
+		},
+	}
+}
+
+type launchdarklyFeatureFlagTarget struct {
+	ContextKey     string
+	ContextKind    string
+	Variation      int32
+	VariationName  *string
+	VariationValue interface{}
+	FlagKey        string
+	ProjectKey     string
+	EnvironmentKey string
+}
+
+// LIST FUNCTION
+
+func listFeatureFlagTargets(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	project := h.Item.(ldapi.Project)
+	projectKey := project.Key
+
+	flagKey := d.EqualsQualString("flag_key")
+	environmentKey := d.EqualsQualString("environment_key")
+	contextKind := d.EqualsQualString("context_kind")
+	contextKey := d.EqualsQualString("context_key")
+
+	err := listFeatureFlagConfigs(ctx, d, projectKey, flagKey, environmentKey, func(flag launchdarklyFeatureFlag, environmentKey string, config ldapi.FeatureFlagConfig) bool {
+		// User targets are returned in targets, targets of every other context
+		// kind in contextTargets
+		targets := append(append([]ldapi.Target{}, config.Targets...), config.ContextTargets...)
+
+		for _, target := range targets {
+			kind := "user"
+			if target.ContextKind != nil && *target.ContextKind != "" {
+				kind = *target.ContextKind
+			}
+			if contextKind != "" && contextKind != kind {
+				continue
+			}
+
+			row := launchdarklyFeatureFlagTarget{
+				ContextKind:    kind,
+				Variation:      target.Variation,
+				FlagKey:        flag.Key,
+				ProjectKey:     projectKey,
+				EnvironmentKey: environmentKey,
+			}
+			if int(target.Variation) < len(flag.Variations) {
+				row.VariationName = flag.Variations[target.Variation].Name
+				row.VariationValue = flag.Variations[target.Variation].Value
+			}
+
+			for _, key := range target.Values {
+				if contextKey != "" && contextKey != key {
+					continue
+				}
+				row.ContextKey = key
+				d.StreamListItem(ctx, row)
+				if d.RowsRemaining(ctx) == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error("launchdarkly_feature_flag_target.listFeatureFlagTargets", "api_error", err)
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,408 @@
+package launchdarkly
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tablelaunchdarklyPermissionCheck(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "launchdarkly_permission_check",
+		Description: "Check whether an account member or access token is allowed an action on a resource, by evaluating its roles and policy statements.",
+		List: &plugin.ListConfig{
+			Hydrate: listPermissionChecks,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "principal_id", Require: plugin.Required},
+				{Name: "action", Require: plugin.Required},
+				{Name: "resource", Require: plugin.Required},
+				{Name: "principal_type", Require: plugin.Optional},
+			},
+			RetryConfig: &plugin.RetryConfig{
+				ShouldRetryErrorFunc: shouldRetryError([]int{http.StatusTooManyRequests}),
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "principal_id",
+				Description: "The ID of the account member or access token.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "principal_type",
+				Description: "The type of the principal: account_member or access_token.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "principal_name",
+				Description: "The email address of the account member, or the name of the access token.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PrincipalName").NullIfZero(),
+			},
+			{
+				Name:        "principal_role",
+				Description: "The built-in role of the principal: reader, writer, admin, owner or no_access.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PrincipalRole").NullIfZero(),
+			},
+			{
+				Name:        "action",
+				Description: "The action checked, e.g. updateOn.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "resource",
+				Description: "The resource specifier of the resource checked, e.g. proj/web:env/production:flag/checkout.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "allowed",
+				Description: "Whether the principal is allowed the action on the resource.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "effect",
+				Description: "The outcome of the check: allow or deny.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "reason",
+				Description: "What decided the outcome: statement, if a policy statement allowed or denied the action, built_in_role, if a built-in role allowed it, or implicit_deny, if nothing allowed it.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "built_in_role",
+				Description: "The built-in role that allowed the action, for decisions by built-in role. Custom roles with reader base permissions allow the reader role.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("BuiltInRole").NullIfZero(),
+			},
+			{
+				Name:        "source_type",
+				Description: "The type of the source of the deciding statement: account_member, team or access_token.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Statement.SourceType"),
+			},
+			{
+				Name:        "source_id",
+				Description: "The ID of the source of the deciding statement: the ID of the account member or access token, or the key of the team.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Statement.SourceId"),
+			},
+			{
+				Name:        "role_key",
+				Description: "The key of the custom role of the deciding statement, if any.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Statement.RoleKey").NullIfZero(),
+			},
+			{
+				Name:        "statement_index",
+				Description: "The index of the deciding statement in the policy, inline role or permission grants it is defined in.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("Statement.StatementIndex"),
+			},
+			{
+				Name:        "statement",
+				Description: "The deciding statement.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Statement.Statement"),
+			},
+			{
+				Name:        "matching_statements",
+				Description: "Every statement of the principal that matches the action and resource, with its source.",
+				Type:        proto.ColumnType_JSON,
+			},
+			// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "Title of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(permissionCheckTitle),
+			},
+		},
+	}
+}
+
+type launchdarklyPermissionCheck struct {
+	policyDecision
+	PrincipalId   string
+	PrincipalType string
+	PrincipalName string
+	PrincipalRole string
+	Action        string
+	Resource      string
+	Effect        string
+}
+
+// permissionPrincipal holds the statements and built-in roles of an account
+// member or access token.
+type permissionPrincipal struct {
+	Type         string
+	Name         string
+	Role         string
+	Statements   []policyStatement
+	BuiltInRoles []string
+}
+
+//// LIST FUNCTION
+
+func listPermissionChecks(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	principalType := d.EqualsQualString("principal_type")
+	if principalType != "" && principalType != policySourceAccountMember && principalType != policySourceAccessToken {
+		return nil, nil
+	}
+
+	// Create client
+	client, err := connect(ctx, d)
+	if err != nil {
+		logger.Error("launchdarkly_permission_check.listPermissionChecks", "connection_error", err)
+		return nil, err
+	}
+
+	// Custom roles are only fetched for principals with custom roles or teams
+	var roles *customRoleIndex
+	getRoles := func() (*customRoleIndex, error) {
+		if roles == nil {
+			index, err := fetchCustomRoleIndex(ctx, client)
+			if err != nil {
+				return nil, err
+			}
+			roles = index
+		}
+		return roles, nil
+	}
+
+	for _, principalId := range getQualStringList(d, "principal_id") {
+		principal, err := getPermissionPrincipal(ctx, client, principalId, principalType, getRoles)
+		if err != nil {
+			logger.Error("launchdarkly_permission_check.listPermissionChecks", "api_error", err)
+			return nil, err
+		}
+		if principal == nil {
+			continue
+		}
+
+		for _, resource := range getQualStringList(d, "resource") {
+			segments := parseResourceSpecifier(resource)
+			if err := validateResourceSpecifier(segments); err != nil {
+				return nil, fmt.Errorf("invalid resource %q: %w", resource, err)
+			}
+			if resourceSpecifierUsesTags(principal.Statements) {
+				segments, err = resourceTags(ctx, client, segments)
+				if err != nil {
+					logger.Error("launchdarkly_permission_check.listPermissionChecks", "api_error", err)
+					return nil, err
+				}
+			}
+
+			for _, action := range getQualStringList(d, "action") {
+				check := launchdarklyPermissionCheck{
+					policyDecision: evaluatePolicy(principal.Statements, principal.BuiltInRoles, action, segments),
+					PrincipalId:    principalId,
+					PrincipalType:  principal.Type,
+					PrincipalName:  principal.Name,
+					PrincipalRole:  principal.Role,
+					Action:         action,
+					Resource:       resource,
+					Effect:         "deny",
+				}
+				if check.Allowed {
+					check.Effect = "allow"
+				}
+				d.StreamListItem(ctx, check)
+
+				// Context can be cancelled due to manual cancellation or the limit has been hit
+				if d.RowsRemaining(ctx) == 0 {
+					return nil, nil
+				}
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// getPermissionPrincipal fetches the account member or access token with the
+// given ID, trying both if principalType is empty, and collects its statements
+// and built-in roles. It returns nil if there is no such principal.
+func getPermissionPrincipal(ctx context.Context, client *ldapi.APIClient, id string, principalType string, getRoles func() (*customRoleIndex, error)) (*permissionPrincipal, error) {
+	if principalType == "" || principalType == policySourceAccountMember {
+		member, resp, err := client.AccountMembersApi.GetMember(ctx, id).Execute()
+		if err != nil {
+			err = apiError(resp, err)
+			if !isNotFoundError(err) {
+				return nil, err
+			}
+		} else {
+			principal := &permissionPrincipal{
+				Type: policySourceAccountMember,
+				Name: member.Email,
+				Role: member.Role,
+			}
+			// The built-in role of a member is only in effect if the member has
+			// no custom roles
+			if len(member.CustomRoles) == 0 {
+				principal.BuiltInRoles = []string{member.Role}
+			}
+			if len(member.CustomRoles) > 0 || len(member.Teams) > 0 {
+				roles, err := getRoles()
+				if err != nil {
+					return nil, err
+				}
+				principal.Statements = append(accountMemberStatements(*member, roles.ByKey), accountMemberTeamStatements(*member, roles.ByKey)...)
+				keys := append([]string{}, member.CustomRoles...)
+				for _, team := range member.Teams {
+					keys = append(keys, team.CustomRoleKeys...)
+				}
+				principal.BuiltInRoles = append(principal.BuiltInRoles, customRoleBasePermissions(roles.ByKey, keys)...)
+			} else {
+				principal.Statements = accountMemberStatements(*member, nil)
+			}
+			return principal, nil
+		}
+	}
+
+	if principalType == "" || principalType == policySourceAccessToken {
+		token, resp, err := client.AccessTokensApi.GetToken(ctx, id).Execute()
+		if err != nil {
+			err = apiError(resp, err)
+			if isNotFoundError(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		principal := &permissionPrincipal{Type: policySourceAccessToken}
+		if token.Name != nil {
+			principal.Name = *token.Name
+		}
+		if token.Role != nil {
+			principal.Role = *token.Role
+			// As for members, the built-in role of a token is only in effect if
+			// the token has no custom roles
+			if len(token.CustomRoleIds) == 0 {
+				principal.BuiltInRoles = []string{*token.Role}
+			}
+		}
+		if len(token.CustomRoleIds) > 0 {
+			roles, err := getRoles()
+			if err != nil {
+				return nil, err
+			}
+			principal.Statements = accessTokenStatements(*token, roles.ById)
+			keys := []string{}
+			for _, roleId := range token.CustomRoleIds {
+				if role, ok := roles.ById[roleId]; ok {
+					keys = append(keys, role.Key)
+				}
+			}
+			principal.BuiltInRoles = append(principal.BuiltInRoles, customRoleBasePermissions(roles.ByKey, keys)...)
+		} else {
+			principal.Statements = accessTokenStatements(*token, nil)
+		}
+		return principal, nil
+	}
+
+	return nil, nil
+}
+
+// customRoleBasePermissions returns the base permissions of the custom roles
+// with the given keys, which grant the reader role unless they are no_access.
+func customRoleBasePermissions(rolesByKey map[string]ldapi.CustomRole, keys []string) []string {
+	for _, key := range keys {
+		role, ok := rolesByKey[key]
+		if ok && role.BasePermissions != nil && *role.BasePermissions == "reader" {
+			return []string{"reader"}
+		}
+	}
+	return nil
+}
+
+// validateResourceSpecifier checks that a resource to check names a single
+// resource of each level, e.g. proj/web:env/production:flag/checkout.
+func validateResourceSpecifier(segments []resourceSpecifierSegment) error {
+	if len(segments) == 0 {
+		return fmt.Errorf("expected a resource specifier, e.g. proj/web:env/production:flag/checkout")
+	}
+	for _, segment := range segments {
+		if segment.Type == "" {
+			return fmt.Errorf("each level of the resource specifier must have a resource type")
+		}
+		if strings.Contains(segment.Key, "*") {
+			return fmt.Errorf("the key %q of %s must not be a pattern", segment.Key, segment.Type)
+		}
+	}
+	return nil
+}
+
+// resourceTags returns a copy of segments with the tags of the projects,
+// environments, flags and segments it names, so that resource specifiers can
+// match them by tag. Tags given in the resource to check are kept, and
+// resources that do not exist have no tags.
+func resourceTags(ctx context.Context, client *ldapi.APIClient, segments []resourceSpecifierSegment) ([]resourceSpecifierSegment, error) {
+	tagged := append([]resourceSpecifierSegment{}, segments...)
+	projectKey := resourceSpecifierKey(segments, "proj")
+	environmentKey := resourceSpecifierKey(segments, "env")
+
+	for i, segment := range tagged {
+		if segment.Tags != nil {
+			continue
+		}
+
+		var tags []string
+		var resp *http.Response
+		var err error
+		switch {
+		case segment.Type == "proj":
+			var project *ldapi.Project
+			project, resp, err = client.ProjectsApi.GetProject(ctx, segment.Key).Execute()
+			if err == nil {
+				tags = project.Tags
+			}
+		case segment.Type == "env" && projectKey != "":
+			var environment *ldapi.Environment
+			environment, resp, err = client.EnvironmentsApi.GetEnvironment(ctx, projectKey, segment.Key).Execute()
+			if err == nil {
+				tags = environment.Tags
+			}
+		case segment.Type == "flag" && projectKey != "":
+			var flag *ldapi.FeatureFlag
+			flag, resp, err = client.FeatureFlagsApi.GetFeatureFlag(ctx, projectKey, segment.Key).Execute()
+			if err == nil {
+				tags = flag.Tags
+			}
+		case segment.Type == "segment" && projectKey != "" && environmentKey != "":
+			var userSegment *ldapi.UserSegment
+			userSegment, resp, err = client.SegmentsApi.GetSegment(ctx, projectKey, environmentKey, segment.Key).Execute()
+			if err == nil {
+				tags = userSegment.Tags
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			err = apiError(resp, err)
+			if isNotFoundError(err) {
+				continue
+			}
+			return nil, err
+		}
+		tagged[i].Tags = tags
+	}
+	return tagged, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func permissionCheckTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	check := d.HydrateItem.(launchdarklyPermissionCheck)
+	return check.Action + " " + check.Resource, nil
+}
@@ -0,0 +1,373 @@
+package launchdarkly
+
+import (
+	"context"
+	"net/http"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tablelaunchdarklyPolicyStatement(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "launchdarkly_policy_statement",
+		Description: "Fetch the policy statements of custom roles, account members, teams, access tokens and webhooks, with their resource specifiers parsed.",
+		List: &plugin.ListConfig{
+			Hydrate: listPolicyStatements,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "source_type", Require: plugin.Optional},
+			},
+			RetryConfig: &plugin.RetryConfig{
+				ShouldRetryErrorFunc: shouldRetryError([]int{http.StatusTooManyRequests}),
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "source_type",
+				Description: "The type of the source the statement applies to: custom_role, account_member, team, access_token or webhook.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "source_id",
+				Description: "The ID of the source: the key of a custom role or team, or the ID of an account member, access token or webhook.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "source_name",
+				Description: "The name of the source, or the email address of an account member.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("SourceName").NullIfZero(),
+			},
+			{
+				Name:        "role_key",
+				Description: "The key of the custom role the statement is defined in. Null for the inline role of an access token, the permission grants of an account member and the statements of a webhook.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("RoleKey").NullIfZero(),
+			},
+			{
+				Name:        "statement_index",
+				Description: "The index of the statement in the policy, inline role, permission grants or webhook statements it is defined in.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "effect",
+				Description: "Whether the statement allows or denies the actions: allow or deny.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "actions",
+				Description: "The actions the statement applies to.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "not_actions",
+				Description: "The statement applies to every action not in this list.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "action_set",
+				Description: "The set of related actions allowed by a permission grant of an account member, e.g. maintainTeam.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ActionSet").NullIfZero(),
+			},
+			{
+				Name:        "resources",
+				Description: "The resource specifiers of the resources the statement applies to.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "not_resources",
+				Description: "The statement applies to every resource not matching these resource specifiers.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "resource",
+				Description: "The resource specifier of the row, one of resources or not_resources, e.g. proj/web:env/production:flag/*.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Resource").NullIfZero(),
+			},
+			{
+				Name:        "negated",
+				Description: "Whether resource is one of the not_resources of the statement.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "project_key",
+				Description: "The project key or key pattern of the resource specifier, e.g. web or *.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ProjectKey").NullIfZero(),
+			},
+			{
+				Name:        "project_tags",
+				Description: "The tags a project must have to match the resource specifier.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "environment_key",
+				Description: "The environment key or key pattern of the resource specifier, e.g. production or *.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("EnvironmentKey").NullIfZero(),
+			},
+			{
+				Name:        "environment_tags",
+				Description: "The tags an environment must have to match the resource specifier.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "resource_type",
+				Description: "The type of the resource matched by the resource specifier, e.g. proj, env, flag, segment, role or member.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ResourceType").NullIfZero(),
+			},
+			{
+				Name:        "resource_key",
+				Description: "The key or key pattern of the resource matched by the resource specifier.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ResourceKey").NullIfZero(),
+			},
+			{
+				Name:        "resource_tags",
+				Description: "The tags the resource must have to match the resource specifier.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "resource_specifier",
+				Description: "Each level of the resource specifier, from the outermost resource to the innermost, with its type, key and tags.",
+				Type:        proto.ColumnType_JSON,
+			},
+			// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "Title of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Resource").NullIfZero(),
+			},
+		},
+	}
+}
+
+type launchdarklyPolicyStatement struct {
+	policyStatement
+	Resource          string
+	Negated           bool
+	ResourceSpecifier []resourceSpecifierSegment
+	ProjectKey        string
+	ProjectTags       []string
+	EnvironmentKey    string
+	EnvironmentTags   []string
+	ResourceType      string
+	ResourceKey       string
+	ResourceTags      []string
+}
+
+// newLaunchdarklyPolicyStatements returns a row for each resource specifier of
+// a statement, or a single row without a resource specifier if the statement
+// has none.
+func newLaunchdarklyPolicyStatements(statement policyStatement) []launchdarklyPolicyStatement {
+	rows := []launchdarklyPolicyStatement{}
+	add := func(resource string, negated bool) {
+		segments := parseResourceSpecifier(resource)
+		row := launchdarklyPolicyStatement{
+			policyStatement:   statement,
+			Resource:          resource,
+			Negated:           negated,
+			ResourceSpecifier: segments,
+			ProjectKey:        resourceSpecifierKey(segments, "proj"),
+			ProjectTags:       resourceSpecifierTags(segments, "proj"),
+			EnvironmentKey:    resourceSpecifierKey(segments, "env"),
+			EnvironmentTags:   resourceSpecifierTags(segments, "env"),
+		}
+		if len(segments) > 0 {
+			innermost := segments[len(segments)-1]
+			row.ResourceType = innermost.Type
+			row.ResourceKey = innermost.Key
+			row.ResourceTags = innermost.Tags
+		}
+		rows = append(rows, row)
+	}
+
+	for _, resource := range statement.Resources {
+		add(resource, false)
+	}
+	for _, resource := range statement.NotResources {
+		add(resource, true)
+	}
+	if len(rows) == 0 {
+		add("", false)
+	}
+	return rows
+}
+
+//// LIST FUNCTION
+
+func listPolicyStatements(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	sourceTypes := policySourceTypes
+	if d.EqualsQuals["source_type"] != nil {
+		sourceTypes = getQualStringList(d, "source_type")
+	}
+
+	// Create client
+	client, err := connect(ctx, d)
+	if err != nil {
+		logger.Error("launchdarkly_policy_statement.listPolicyStatements", "connection_error", err)
+		return nil, err
+	}
+
+	// stream streams the rows of statements and reports whether more rows
+	// are wanted
+	stream := func(statements []policyStatement) bool {
+		for _, statement := range statements {
+			for _, row := range newLaunchdarklyPolicyStatements(statement) {
+				d.StreamListItem(ctx, row)
+				// Context can be cancelled due to manual cancellation or the limit has been hit
+				if d.RowsRemaining(ctx) == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	// Custom roles are assigned to every source but webhooks
+	var roles *customRoleIndex
+	for _, sourceType := range sourceTypes {
+		switch sourceType {
+		case policySourceCustomRole, policySourceAccountMember, policySourceTeam, policySourceAccessToken:
+			roles, err = fetchCustomRoleIndex(ctx, client)
+			if err != nil {
+				logger.Error("launchdarkly_policy_statement.listPolicyStatements", "api_error", err)
+				return nil, err
+			}
+		}
+		if roles != nil {
+			break
+		}
+	}
+
+	for _, sourceType := range sourceTypes {
+		var more bool
+		switch sourceType {
+		case policySourceCustomRole:
+			more = streamCustomRolePolicyStatements(roles, stream)
+		case policySourceAccountMember:
+			more, err = streamAccountMemberPolicyStatements(ctx, client, roles, stream)
+		case policySourceTeam:
+			more, err = streamTeamPolicyStatements(ctx, client, roles, stream)
+		case policySourceAccessToken:
+			more, err = streamAccessTokenPolicyStatements(ctx, client, roles, stream)
+		case policySourceWebhook:
+			more, err = streamWebhookPolicyStatements(ctx, client, stream)
+		default:
+			continue
+		}
+		if err != nil {
+			logger.Error("launchdarkly_policy_statement.listPolicyStatements", "api_error", err)
+			return nil, err
+		}
+		if !more {
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func streamCustomRolePolicyStatements(roles *customRoleIndex, stream func([]policyStatement) bool) bool {
+	for _, role := range roles.Roles {
+		if !stream(customRoleStatements(policySourceCustomRole, role.Key, role.Name, role)) {
+			return false
+		}
+	}
+	return true
+}
+
+func streamAccountMemberPolicyStatements(ctx context.Context, client *ldapi.APIClient, roles *customRoleIndex, stream func([]policyStatement) bool) (bool, error) {
+	count := 0
+	params := client.AccountMembersApi.GetMembers(ctx)
+	for {
+		members, resp, err := params.Execute()
+		if err != nil {
+			return false, apiError(resp, err)
+		}
+		for _, member := range members.Items {
+			if !stream(accountMemberStatements(member, roles.ByKey)) {
+				return false, nil
+			}
+		}
+		count += len(members.Items)
+		if len(members.Items) == 0 || members.TotalCount == nil || count >= int(*members.TotalCount) {
+			return true, nil
+		}
+		params = params.Offset(int64(count))
+	}
+}
+
+func streamTeamPolicyStatements(ctx context.Context, client *ldapi.APIClient, roles *customRoleIndex, stream func([]policyStatement) bool) (bool, error) {
+	count := 0
+	params := client.TeamsApi.GetTeams(ctx)
+	for {
+		teams, resp, err := params.Execute()
+		if err != nil {
+			return false, apiError(resp, err)
+		}
+		for _, team := range teams.Items {
+			if team.Key == nil {
+				continue
+			}
+			name := ""
+			if team.Name != nil {
+				name = *team.Name
+			}
+			keys, err := teamRoleKeys(ctx, client, *team.Key)
+			if err != nil {
+				return false, err
+			}
+			for _, key := range keys {
+				role, ok := roles.ByKey[key]
+				if !ok {
+					continue
+				}
+				if !stream(customRoleStatements(policySourceTeam, *team.Key, name, role)) {
+					return false, nil
+				}
+			}
+		}
+		count += len(teams.Items)
+		if len(teams.Items) == 0 || teams.TotalCount == nil || count >= int(*teams.TotalCount) {
+			return true, nil
+		}
+		params = params.Offset(int64(count))
+	}
+}
+
+func streamAccessTokenPolicyStatements(ctx context.Context, client *ldapi.APIClient, roles *customRoleIndex, stream func([]policyStatement) bool) (bool, error) {
+	more := true
+	// Admins can see the personal access tokens of every member
+	err := getJSONPages(ctx, client, "/api/v2/tokens?showAll=true", maxAccessTokensPageSize, func(token ldapi.Token) bool {
+		more = stream(accessTokenStatements(token, roles.ById))
+		return more
+	})
+	if err != nil {
+		return false, err
+	}
+	return more, nil
+}
+
+func streamWebhookPolicyStatements(ctx context.Context, client *ldapi.APIClient, stream func([]policyStatement) bool) (bool, error) {
+	webhooks, resp, err := client.WebhooksApi.GetAllWebhooks(ctx).Execute()
+	if err != nil {
+		return false, apiError(resp, err)
+	}
+	for _, webhook := range webhooks.Items {
+		if !stream(webhookStatements(webhook)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
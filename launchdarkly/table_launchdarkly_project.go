@@ -109,6 +109,49 @@ func listProjects(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDat
 		return nil, err
 	}
 
+	// Project-scoped tables use listProjects as their parent hydrate. When the
+	// query specifies project_key, only those projects are passed on to the
+	// child hydrate instead of enumerating every project in the account.
+	if projectKeys := getQualStringList(d, "project_key"); projectKeys != nil {
+		for _, key := range projectKeys {
+			if !projectInScope(d, key) {
+				continue
+			}
+			d.StreamListItem(ctx, ldapi.Project{Key: key})
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+		return nil, nil
+	}
+
+	// A connection restricted to projects by key fetches only those projects.
+	// Projects cannot be fetched by key with a filter, so filtered queries list
+	// the projects instead.
+	if projectKeys := scopedProjectKeys(d); projectKeys != nil && d.EqualsQualString("filter") == "" {
+		for _, key := range projectKeys {
+			getParams := client.ProjectsApi.GetProject(ctx, key)
+			if d.EqualsQuals["expand"].GetStringValue() != "" {
+				getParams = getParams.Expand(d.EqualsQualString("expand"))
+			}
+			project, resp, err := getParams.Execute()
+			if err != nil {
+				err = apiError(resp, err)
+				if isNotFoundError(err) {
+					continue
+				}
+				logger.Error("launchdarkly_project.listProjects", "api_error", err)
+				return nil, err
+			}
+			scopeProjectEnvironments(d, project)
+			d.StreamListItem(ctx, *project)
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+		return nil, nil
+	}
+
 	params := client.ProjectsApi.GetProjects(ctx)
 
 	if d.QueryContext.Limit != nil {
@@ -126,19 +169,24 @@ func listProjects(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDat
 	}
 
 	if d.EqualsQuals["expand"].GetStringValue() != "" {
-		params = params.Filter(d.EqualsQualString("expand"))
+		params = params.Expand(d.EqualsQualString("expand"))
 	}
 
 	count := 0
 
 	for {
-		projects, _, err := params.Execute()
+		projects, resp, err := params.Execute()
 		if err != nil {
+			err = apiError(resp, err)
 			plugin.Logger(ctx).Error("launchdarkly_project.listProjects", "api_error", err)
 			return nil, err
 		}
 
 		for _, project := range projects.Items {
+			if !projectInScope(d, project.Key) {
+				continue
+			}
+			scopeProjectEnvironments(d, &project)
 			d.StreamListItem(ctx, project)
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -150,7 +198,7 @@ func listProjects(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDat
 		if count >= int(projects.GetTotalCount()) {
 			break
 		}
-		params.Offset(int64(count))
+		params = params.Offset(int64(count))
 	}
 
 	return nil, nil
@@ -160,6 +208,9 @@ func listProjects(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDat
 func getProject(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
 	logger := plugin.Logger(ctx)
 	key := d.EqualsQualString("key")
+	if !projectInScope(d, key) {
+		return nil, nil
+	}
 
 	// Create client
 	client, err := connect(ctx, d)
@@ -168,11 +219,13 @@ func getProject(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData)
 		return nil, err
 	}
 
-	project, _, err := client.ProjectsApi.GetProject(ctx, key).Execute()
+	project, resp, err := client.ProjectsApi.GetProject(ctx, key).Execute()
 	if err != nil {
+		err = apiError(resp, err)
 		logger.Error("launchdarkly_project.getProject", "api_error", err)
 		return nil, err
 	}
+	scopeProjectEnvironments(d, project)
 
 	return *project, nil
 }
@@ -188,8 +241,9 @@ func getFlagDefaultsForProject(ctx context.Context, d *plugin.QueryData, h *plug
 		return nil, err
 	}
 
-	flag, _, err := client.ProjectsApi.GetFlagDefaultsByProject(ctx, key).Execute()
+	flag, resp, err := client.ProjectsApi.GetFlagDefaultsByProject(ctx, key).Execute()
 	if err != nil {
+		err = apiError(resp, err)
 		logger.Error("launchdarkly_project.getFlagDefaultsForProject", "api_error", err)
 		return nil, err
 	}
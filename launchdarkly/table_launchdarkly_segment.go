@@ -0,0 +1,273 @@
+package launchdarkly
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	ldapi "github.com/launchdarkly/api-client-go/v13"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tablelaunchdarklySegment(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "launchdarkly_segment",
+		Description: "Fetch the segments of each environment.",
+		List: &plugin.ListConfig{
+			ParentHydrate: listProjects,
+			Hydrate:       listSegments,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "project_key", Require: plugin.Optional},
+				{Name: "environment_key", Require: plugin.Optional},
+			},
+			RetryConfig: &plugin.RetryConfig{
+				ShouldRetryErrorFunc: shouldRetryError([]int{http.StatusTooManyRequests}),
+			},
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: plugin.AllColumns([]string{"project_key", "environment_key", "key"}),
+			Hydrate:    getSegment,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "key",
+				Description: "A unique key used to reference the segment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "name",
+				Description: "A human-friendly name for the segment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "project_key",
+				Description: "The key of the project.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "environment_key",
+				Description: "The key of the environment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "description",
+				Description: "A description of the segment's purpose.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "unbounded",
+				Description: "Whether the segment is a big segment, whose members are stored outside of LaunchDarkly's flag data.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "unbounded_context_kind",
+				Description: "The context kind of the members of a big segment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "generation",
+				Description: "For big segments, how many times the segment has been created.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "version",
+				Description: "The version of the segment.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "deleted",
+				Description: "Whether the segment has been deleted.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "creation_date",
+				Description: "Time when the segment was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("CreationDate").Transform(transform.UnixMsToTimestamp),
+			},
+			{
+				Name:        "import_in_progress",
+				Description: "Whether an import is in progress for a big segment.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "external",
+				Description: "The external data store backing a big segment, if any.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "included_count",
+				Description: "The number of user keys individually included in the segment.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "excluded_count",
+				Description: "The number of user keys individually excluded from the segment.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "included_contexts_count",
+				Description: "The number of context keys of other context kinds individually included in the segment.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "excluded_contexts_count",
+				Description: "The number of context keys of other context kinds individually excluded from the segment.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "rules_count",
+				Description: "The number of targeting rules of the segment.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "tags",
+				Description: "Tags for the segment.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "included",
+				Description: "The user keys individually included in the segment, regardless of its rules.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "excluded",
+				Description: "The user keys individually excluded from the segment's rules.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "included_contexts",
+				Description: "The context keys of other context kinds individually included in the segment, by context kind.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "excluded_contexts",
+				Description: "The context keys of other context kinds individually excluded from the segment's rules, by context kind.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "rules",
+				Description: "The targeting rules of the segment.",
+				Type:        proto.ColumnType_JSON,
+			},
+			// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "Title of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Name"),
+			},
+		},
+	}
+}
+
+type launchdarklySegment struct {
+	ldapi.UserSegment
+	ProjectKey            string
+	EnvironmentKey        string
+	IncludedCount         int
+	ExcludedCount         int
+	IncludedContextsCount int
+	ExcludedContextsCount int
+	RulesCount            int
+}
+
+func newLaunchdarklySegment(segment ldapi.UserSegment, projectKey string, environmentKey string) launchdarklySegment {
+	return launchdarklySegment{
+		UserSegment:           segment,
+		ProjectKey:            projectKey,
+		EnvironmentKey:        environmentKey,
+		IncludedCount:         len(segment.Included),
+		ExcludedCount:         len(segment.Excluded),
+		IncludedContextsCount: segmentTargetsCount(segment.IncludedContexts),
+		ExcludedContextsCount: segmentTargetsCount(segment.ExcludedContexts),
+		RulesCount:            len(segment.Rules),
+	}
+}
+
+// segmentTargetsCount returns the number of context keys of segment targets,
+// which group context keys by context kind.
+func segmentTargetsCount(targets []ldapi.SegmentTarget) int {
+	count := 0
+	for _, target := range targets {
+		count += len(target.Values)
+	}
+	return count
+}
+
+// maxSegmentsPageSize is the page size used to list segments.
+const maxSegmentsPageSize = 50
+
+// LIST FUNCTION
+
+func listSegments(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	project := h.Item.(ldapi.Project)
+	projectKey := project.Key
+
+	// Create client
+	client, err := connect(ctx, d)
+	if err != nil {
+		logger.Error("launchdarkly_segment.listSegments", "connection_error", err)
+		return nil, err
+	}
+
+	environmentKeys, err := projectEnvironmentKeys(ctx, d, projectKey, d.EqualsQualString("environment_key"))
+	if err != nil {
+		logger.Error("launchdarkly_segment.listSegments", "api_error", err)
+		return nil, err
+	}
+
+	for _, environmentKey := range environmentKeys {
+		more := true
+		path := fmt.Sprintf("/api/v2/segments/%s/%s", url.PathEscape(projectKey), url.PathEscape(environmentKey))
+		err := getJSONPages(ctx, client, path, maxSegmentsPageSize, func(segment ldapi.UserSegment) bool {
+			d.StreamListItem(ctx, newLaunchdarklySegment(segment, projectKey, environmentKey))
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			more = d.RowsRemaining(ctx) != 0
+			return more
+		})
+		if err != nil {
+			logger.Error("launchdarkly_segment.listSegments", "api_error", err)
+			return nil, err
+		}
+		if !more {
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func getSegment(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	projectKey := d.EqualsQualString("project_key")
+	environmentKey := d.EqualsQualString("environment_key")
+	segmentKey := d.EqualsQualString("key")
+	if !projectInScope(d, projectKey) || !environmentInScope(d, environmentKey) {
+		return nil, nil
+	}
+
+	// Create client
+	client, err := connect(ctx, d)
+	if err != nil {
+		logger.Error("launchdarkly_segment.getSegment", "connection_error", err)
+		return nil, err
+	}
+
+	segment, resp, err := client.SegmentsApi.GetSegment(ctx, projectKey, environmentKey, segmentKey).Execute()
+	if err != nil {
+		err = apiError(resp, err)
+		logger.Error("launchdarkly_segment.getSegment", "api_error", err)
+		return nil, err
+	}
+
+	return newLaunchdarklySegment(*segment, projectKey, environmentKey), nil
+}
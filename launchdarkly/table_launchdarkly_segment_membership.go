@@ -0,0 +1,118 @@
+package launchdarkly
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tablelaunchdarklySegmentMembership(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "launchdarkly_segment_membership",
+		Description: "Check whether a context is included in or excluded from a big segment.",
+		List: &plugin.ListConfig{
+			Hydrate: listSegmentMemberships,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "project_key", Require: plugin.Required},
+				{Name: "environment_key", Require: plugin.Required},
+				{Name: "segment_key", Require: plugin.Required},
+				{Name: "context_key", Require: plugin.Required},
+			},
+			RetryConfig: &plugin.RetryConfig{
+				ShouldRetryErrorFunc: shouldRetryError([]int{http.StatusTooManyRequests}),
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "project_key",
+				Description: "The key of the project.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "environment_key",
+				Description: "The key of the environment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "segment_key",
+				Description: "The key of the big segment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "context_key",
+				Description: "The key of the context.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "included",
+				Description: "Whether the context is included in the segment. Included contexts are always members of the segment.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "excluded",
+				Description: "Whether the context is excluded from the segment's rules. Excluded contexts are still members of the segment if they are included.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "Title of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ContextKey"),
+			},
+		},
+	}
+}
+
+type launchdarklySegmentMembership struct {
+	ProjectKey     string
+	EnvironmentKey string
+	SegmentKey     string
+	ContextKey     string
+	Included       bool
+	Excluded       bool
+}
+
+//// LIST FUNCTION
+
+func listSegmentMemberships(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	projectKey := d.EqualsQualString("project_key")
+	environmentKey := d.EqualsQualString("environment_key")
+	segmentKey := d.EqualsQualString("segment_key")
+	contextKey := d.EqualsQualString("context_key")
+	if !projectInScope(d, projectKey) || !environmentInScope(d, environmentKey) {
+		return nil, nil
+	}
+
+	// Create client
+	client, err := connect(ctx, d)
+	if err != nil {
+		logger.Error("launchdarkly_segment_membership.listSegmentMemberships", "connection_error", err)
+		return nil, err
+	}
+
+	target, resp, err := client.SegmentsApi.GetSegmentMembershipForContext(ctx, projectKey, environmentKey, segmentKey, contextKey).Execute()
+	if err != nil {
+		err = apiError(resp, err)
+		logger.Error("launchdarkly_segment_membership.listSegmentMemberships", "api_error", err)
+		return nil, err
+	}
+
+	d.StreamListItem(ctx, launchdarklySegmentMembership{
+		ProjectKey:     projectKey,
+		EnvironmentKey: environmentKey,
+		SegmentKey:     segmentKey,
+		ContextKey:     contextKey,
+		Included:       target.Included,
+		Excluded:       target.Excluded,
+	})
+
+	return nil, nil
+}
@@ -155,8 +155,9 @@ func listTeams(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData)
 	count := 0
 
 	for {
-		teams, _, err := params.Execute()
+		teams, resp, err := params.Execute()
 		if err != nil {
+			err = apiError(resp, err)
 			logger.Error("launchdarkly_team.listTeams", "api_error", err)
 			return nil, err
 		}
@@ -189,8 +190,9 @@ func getTeam(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (i
 		return nil, err
 	}
 
-	team, _, err := client.TeamsApi.GetTeam(ctx, key).Execute()
+	team, resp, err := client.TeamsApi.GetTeam(ctx, key).Execute()
 	if err != nil {
+		err = apiError(resp, err)
 		logger.Error("launchdarkly_team.getTeam", "api_error", err)
 		return nil, err
 	}
@@ -0,0 +1,23 @@
+package launchdarkly
+
+import (
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+// getQualStringList returns the values of an equals qual, which holds a list
+// for "column in (...)" and a single value for "column = ..."
+func getQualStringList(d *plugin.QueryData, column string) []string {
+	qual := d.EqualsQuals[column]
+	if qual == nil {
+		return nil
+	}
+
+	values := []string{}
+	if list := qual.GetListValue(); list != nil {
+		for _, value := range list.Values {
+			values = append(values, value.GetStringValue())
+		}
+		return values
+	}
+	return append(values, qual.GetStringValue())
+}